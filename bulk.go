@@ -0,0 +1,455 @@
+package mapquest
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultBulkConcurrency is the number of concurrent requests of a bulk
+// geocoding run if BulkGeocodeOptions.Concurrency is not set.
+const DefaultBulkConcurrency = 4
+
+// BulkColumnHeaders are the columns a bulk geocoding run appends to every
+// row of its input.
+var BulkColumnHeaders = []string{"lat", "lng", "quality", "error"}
+
+// BulkColumns maps the columns of a CSV file to the location of a
+// geocoding request, by their name in the header row. Set either Location
+// to the column holding a single line address, or the fields of Address to
+// the columns holding the parts of a structured address.
+type BulkColumns struct {
+	Location string
+	Address  GeocodeAddress
+}
+
+// BulkGeocodeOptions configures a bulk geocoding run.
+type BulkGeocodeOptions struct {
+	// Columns maps the input columns to the location of the requests.
+	Columns BulkColumns
+	// Request is the template of all requests, e.g. to set a BoundingBox or
+	// an IntlMode. Its Location and Address are filled in for every row.
+	Request GeocodeAddressRequest
+	// Concurrency is the maximum number of requests in flight. It defaults
+	// to DefaultBulkConcurrency. Requests are additionally subject to the
+	// rate limit of the client.
+	Concurrency int
+	// Checkpoint is the path of a file the results are recorded in as they
+	// come in. Rows recorded by an earlier, interrupted run with the same
+	// location are not geocoded again. Rows failing for transient reasons,
+	// e.g. server errors, are not recorded and retried on the next run.
+	Checkpoint string
+}
+
+// BulkGeocodeStats summarizes a bulk geocoding run.
+type BulkGeocodeStats struct {
+	// Rows is the number of rows written.
+	Rows int
+	// Geocoded is the number of rows geocoded by this run.
+	Geocoded int
+	// Resumed is the number of rows taken from the checkpoint.
+	Resumed int
+	// Failed is the number of rows written with an error.
+	Failed int
+}
+
+// BulkGeocode geocodes the rows of the CSV data read from r, and writes
+// them to w with the columns in BulkColumnHeaders appended. The first row
+// of r must be a header. Rows are written in input order, with the first
+// result of each row. Row errors are written to the error column; errors
+// affecting all rows, like an invalid key or an exceeded quota, stop the
+// run and are returned together with the stats of the rows written so
+// far.
+func (api *GeocodingAPI) BulkGeocode(ctx context.Context, r io.Reader, w io.Writer, opts *BulkGeocodeOptions) (*BulkGeocodeStats, error) {
+	if opts == nil {
+		opts = new(BulkGeocodeOptions)
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultBulkConcurrency
+	}
+
+	in := csv.NewReader(r)
+	in.FieldsPerRecord = -1
+	header, err := in.Read()
+	if err != nil {
+		return nil, err
+	}
+	cols, err := opts.Columns.resolve(header)
+	if err != nil {
+		return nil, err
+	}
+
+	checkpoint, err := openBulkCheckpoint(opts.Checkpoint)
+	if err != nil {
+		return nil, err
+	}
+	defer checkpoint.Close()
+
+	out := csv.NewWriter(w)
+	if err := out.Write(append(header, BulkColumnHeaders...)); err != nil {
+		return nil, err
+	}
+
+	// stop ends the dispatch of rows after a fatal error. Requests in flight
+	// are completed and recorded, as they are paid for anyway.
+	stop, halt := context.WithCancel(ctx)
+	defer halt()
+
+	var (
+		jobs    = make(chan *bulkRow)
+		results = make(chan *bulkRow)
+		// window bounds the rows read ahead of the next row to write
+		window  = make(chan struct{}, 4*concurrency)
+		wg      sync.WaitGroup
+		readErr error
+	)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer close(jobs)
+		for index := 0; ; index++ {
+			record, err := in.Read()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				readErr = err
+				return
+			}
+
+			select {
+			case window <- struct{}{}:
+			case <-stop.Done():
+				return
+			}
+
+			row := cols.row(index, record, &opts.Request)
+			if res, ok := checkpoint.results[index]; ok && res.Query == row.result.Query {
+				row.result = res
+				results <- row
+				continue
+			}
+			select {
+			case jobs <- row:
+			case <-stop.Done():
+				return
+			}
+		}
+	}()
+
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for row := range jobs {
+				if stop.Err() != nil {
+					continue
+				}
+				api.bulkGeocode(ctx, row)
+				if row.fatal != nil {
+					halt()
+				}
+				results <- row
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// write the rows in input order as they come in
+	var (
+		stats    = new(BulkGeocodeStats)
+		pending  = make(map[int]*bulkRow)
+		next     int
+		fatalErr error
+	)
+	fail := func(err error) {
+		if fatalErr == nil {
+			fatalErr = err
+			halt()
+		}
+	}
+	for row := range results {
+		if row.fatal != nil {
+			fail(row.fatal)
+			continue
+		}
+		// record rows finished after a fatal error as well, so resuming
+		// does not geocode them again
+		if row.fresh && row.persist {
+			if err := checkpoint.record(&row.result); err != nil {
+				fail(err)
+			}
+		}
+		if fatalErr != nil {
+			continue
+		}
+
+		pending[row.index] = row
+		for row := pending[next]; row != nil; row = pending[next] {
+			if err := out.Write(row.output()); err != nil {
+				fail(err)
+				break
+			}
+			delete(pending, next)
+			next++
+			<-window
+
+			stats.Rows++
+			if row.fresh {
+				stats.Geocoded++
+			} else {
+				stats.Resumed++
+			}
+			if row.result.Error != "" {
+				stats.Failed++
+			}
+		}
+	}
+
+	out.Flush()
+	switch {
+	case readErr != nil:
+		return stats, readErr
+	case fatalErr != nil:
+		return stats, fatalErr
+	case ctx.Err() != nil:
+		return stats, ctx.Err()
+	}
+	return stats, out.Error()
+}
+
+// bulkGeocode geocodes row, unless it has no location.
+func (api *GeocodingAPI) bulkGeocode(ctx context.Context, row *bulkRow) {
+	row.fresh = true
+	row.persist = true
+	if row.req.Location == "" && row.req.Address == nil {
+		row.result.Error = "no location"
+		return
+	}
+
+	res, err := api.AddressContext(ctx, row.req)
+	switch {
+	case err == nil:
+	case ctx.Err() != nil || errors.Is(err, ErrInvalidKey) || errors.Is(err, ErrQuotaExceeded):
+		row.fatal = err
+		return
+	default:
+		// only record errors caused by the row itself
+		row.persist = errors.Is(err, ErrBadRequest) || errors.Is(err, ErrInvalidIntlMode)
+		row.result.Error = err.Error()
+		return
+	}
+
+	if len(res.Results) == 0 || len(res.Results[0].Locations) == 0 || res.Results[0].Locations[0].LatLong == nil {
+		row.result.Error = "no match"
+		return
+	}
+	loc := res.Results[0].Locations[0]
+	row.result.LatLong = loc.LatLong
+	row.result.Quality = loc.GeocodeQualityCode
+}
+
+// bulkColumnIndexes are the positions of the mapped columns in a row, or -1
+// for unmapped columns.
+type bulkColumnIndexes struct {
+	location                                         int
+	street, city, county, state, postalCode, country int
+}
+
+func (s *BulkColumns) resolve(header []string) (*bulkColumnIndexes, error) {
+	if len(header) > 0 {
+		// spreadsheet exports tend to start with a byte order mark
+		header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	}
+
+	hasAddress := s.Address != (GeocodeAddress{})
+	switch {
+	case s.Location != "" && hasAddress:
+		return nil, ErrLocationConflict
+	case s.Location == "" && !hasAddress:
+		return nil, errors.New("mapquest: bulk geocoding needs a location or address column")
+	}
+
+	var err error
+	index := func(name string) int {
+		if name == "" || err != nil {
+			return -1
+		}
+		for i, h := range header {
+			if strings.TrimSpace(h) == name {
+				return i
+			}
+		}
+		err = fmt.Errorf("mapquest: column %q not found in header", name)
+		return -1
+	}
+
+	cols := &bulkColumnIndexes{
+		location:   index(s.Location),
+		street:     index(s.Address.Street),
+		city:       index(s.Address.City),
+		county:     index(s.Address.County),
+		state:      index(s.Address.State),
+		postalCode: index(s.Address.PostalCode),
+		country:    index(s.Address.Country),
+	}
+	return cols, err
+}
+
+// row creates the request of the row at index from template.
+func (s *bulkColumnIndexes) row(index int, record []string, template *GeocodeAddressRequest) *bulkRow {
+	field := func(i int) string {
+		if i < 0 || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	req := *template
+	req.Location = field(s.location)
+	req.Address = &GeocodeAddress{
+		Street:     field(s.street),
+		City:       field(s.city),
+		County:     field(s.county),
+		State:      field(s.state),
+		PostalCode: field(s.postalCode),
+		Country:    field(s.country),
+	}
+	if *req.Address == (GeocodeAddress{}) {
+		req.Address = nil
+	}
+	if req.Limit == 0 {
+		req.Limit = 1
+	}
+
+	query := req.Location
+	if req.Address != nil {
+		a := req.Address
+		query = strings.Join([]string{a.Street, a.City, a.County, a.State, a.PostalCode, a.Country}, "|")
+	}
+
+	return &bulkRow{
+		index:  index,
+		record: record,
+		req:    &req,
+		result: bulkResult{Row: index, Query: query},
+	}
+}
+
+// bulkRow is a row of a bulk geocoding run.
+type bulkRow struct {
+	index  int
+	record []string
+	req    *GeocodeAddressRequest
+	result bulkResult
+
+	// fresh is set for rows geocoded by this run, persist for fresh rows to
+	// record in the checkpoint.
+	fresh   bool
+	persist bool
+	// fatal is an error stopping the run.
+	fatal error
+}
+
+func (s *bulkRow) output() []string {
+	var lat, lng string
+	if p := s.result.LatLong; p != nil {
+		lat = strconv.FormatFloat(p.Latitude, 'f', -1, 64)
+		lng = strconv.FormatFloat(p.Longitude, 'f', -1, 64)
+	}
+	return append(s.record, lat, lng, s.result.Quality, s.result.Error)
+}
+
+// bulkResult is the result of a row, as recorded in the checkpoint.
+type bulkResult struct {
+	Row     int       `json:"row"`
+	Query   string    `json:"query"`
+	LatLong *GeoPoint `json:"latLng,omitempty"`
+	Quality string    `json:"quality,omitempty"`
+	Error   string    `json:"error,omitempty"`
+}
+
+// bulkCheckpoint is the checkpoint file of a bulk geocoding run, holding
+// one JSON encoded bulkResult per line.
+type bulkCheckpoint struct {
+	file    *os.File
+	results map[int]bulkResult
+}
+
+// openBulkCheckpoint reads the results recorded at path and opens it for
+// appending. An empty path disables the checkpoint.
+func openBulkCheckpoint(path string) (*bulkCheckpoint, error) {
+	s := &bulkCheckpoint{results: make(map[int]bulkResult)}
+	if path == "" {
+		return s, nil
+	}
+
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := bufio.NewScanner(f)
+	lines.Buffer(nil, 1<<20)
+	terminated := true
+	for lines.Scan() {
+		// an interrupted run may leave a partial line behind, skip it
+		var res bulkResult
+		if json.Unmarshal(lines.Bytes(), &res) == nil {
+			s.results[res.Row] = res
+		}
+	}
+	if err := lines.Err(); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	if info, err := f.Stat(); err == nil && info.Size() > 0 {
+		last := make([]byte, 1)
+		if _, err := f.ReadAt(last, info.Size()-1); err == nil {
+			terminated = last[0] == '\n'
+		}
+	}
+	if !terminated {
+		if _, err := f.Write([]byte("\n")); err != nil {
+			f.Close()
+			return nil, err
+		}
+	}
+
+	s.file = f
+	return s, nil
+}
+
+func (s *bulkCheckpoint) record(res *bulkResult) error {
+	if s.file == nil {
+		return nil
+	}
+	data, err := json.Marshal(res)
+	if err != nil {
+		return err
+	}
+	_, err = s.file.Write(append(data, '\n'))
+	return err
+}
+
+func (s *bulkCheckpoint) Close() error {
+	if s.file == nil {
+		return nil
+	}
+	return s.file.Close()
+}
@@ -0,0 +1,166 @@
+package mapquest_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cking/mapquest"
+	"github.com/cking/mapquest/mapquesttest"
+)
+
+// bulkInput returns a CSV file of n addresses.
+func bulkInput(n int) string {
+	var b strings.Builder
+	b.WriteString("id,address\n")
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, "%d,%d Main St\n", i, i)
+	}
+	return b.String()
+}
+
+// geocodedResponse is a successful geocoding response.
+func geocodedResponse() *mapquesttest.Response {
+	return mapquesttest.JSONResponse(&mapquest.GeocodeAddressResponse{
+		Info: &mapquest.ResponseInfo{},
+		Results: []*mapquest.GeocodeAddressResponseEntry{{
+			Locations: []*mapquest.GeocodeAddressResponseLocationEntry{{LatLong: &mapquesttest.DefaultPoint, GeocodeQualityCode: "P1AAA"}},
+		}},
+	})
+}
+
+func bulkGeocode(t *testing.T, client *mapquest.Client, input string, opts *mapquest.BulkGeocodeOptions) ([][]string, *mapquest.BulkGeocodeStats, error) {
+	t.Helper()
+	opts.Columns = mapquest.BulkColumns{Location: "address"}
+	var out bytes.Buffer
+	stats, err := client.Geocoding().BulkGeocode(context.Background(), strings.NewReader(input), &out, opts)
+	rows, csvErr := csv.NewReader(&out).ReadAll()
+	if csvErr != nil {
+		t.Fatal(csvErr)
+	}
+	return rows, stats, err
+}
+
+func checkpointLines(t *testing.T, path string) int {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bytes.Count(data, []byte("\n"))
+}
+
+func TestBulkGeocodeOrder(t *testing.T) {
+	srv := mapquesttest.NewServer()
+	defer srv.Close()
+	// delay some responses, so the workers finish out of order
+	for i := 0; i < 4; i++ {
+		srv.Enqueue(mapquesttest.PathGeocodeAddress, geocodedResponse().WithDelay(time.Duration(40-10*i)*time.Millisecond))
+	}
+
+	rows, stats, err := bulkGeocode(t, srv.Client("key"), bulkInput(50), &mapquest.BulkGeocodeOptions{Concurrency: 8})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Rows != 50 || stats.Geocoded != 50 || stats.Failed != 0 {
+		t.Errorf("got stats %+v", stats)
+	}
+	if want := []string{"id", "address", "lat", "lng", "quality", "error"}; strings.Join(rows[0], ",") != strings.Join(want, ",") {
+		t.Errorf("got header %v, want %v", rows[0], want)
+	}
+	for i, row := range rows[1:] {
+		if row[0] != fmt.Sprint(i) || row[2] != "40.053116" || row[3] != "-76.313603" || row[4] != "P1AAA" || row[5] != "" {
+			t.Errorf("row %d: got %v", i, row)
+		}
+	}
+}
+
+func TestBulkGeocodeResume(t *testing.T) {
+	srv := mapquesttest.NewServer()
+	defer srv.Close()
+	client := srv.Client("key")
+	checkpoint := filepath.Join(t.TempDir(), "checkpoint")
+	input := bulkInput(20)
+
+	// a transient failure is reported, but not recorded
+	srv.Enqueue(mapquesttest.PathGeocodeAddress, mapquesttest.StatusResponse(http.StatusInternalServerError, "Internal error"))
+	_, stats, err := bulkGeocode(t, client, input, &mapquest.BulkGeocodeOptions{Checkpoint: checkpoint})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Failed != 1 {
+		t.Errorf("got stats %+v, want 1 failed row", stats)
+	}
+	if n := checkpointLines(t, checkpoint); n != 19 {
+		t.Errorf("checkpointed %d rows, want 19", n)
+	}
+
+	// resuming only geocodes the failed row
+	srv.Reset()
+	rows, stats, err := bulkGeocode(t, client, input, &mapquest.BulkGeocodeOptions{Checkpoint: checkpoint})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(srv.Requests()); n != 1 {
+		t.Errorf("got %d requests, want 1", n)
+	}
+	if stats.Rows != 20 || stats.Resumed != 19 || stats.Geocoded != 1 || stats.Failed != 0 {
+		t.Errorf("got stats %+v", stats)
+	}
+	for i, row := range rows[1:] {
+		if row[0] != fmt.Sprint(i) || row[4] != "P1AAA" {
+			t.Errorf("row %d: got %v", i, row)
+		}
+	}
+
+	// a finished run makes no requests at all
+	srv.Reset()
+	if _, _, err := bulkGeocode(t, client, input, &mapquest.BulkGeocodeOptions{Checkpoint: checkpoint}); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(srv.Requests()); n != 0 {
+		t.Errorf("got %d requests, want 0", n)
+	}
+}
+
+func TestBulkGeocodeFatalError(t *testing.T) {
+	srv := mapquesttest.NewServer()
+	defer srv.Close()
+	client := srv.Client("key")
+	checkpoint := filepath.Join(t.TempDir(), "checkpoint")
+	input := bulkInput(20)
+
+	// one worker fails with a key error while the other one is still
+	// waiting for its result
+	srv.Enqueue(mapquesttest.PathGeocodeAddress,
+		mapquesttest.StatusResponse(http.StatusForbidden, "The AppKey submitted with this request is invalid.").WithDelay(20*time.Millisecond),
+		geocodedResponse().WithDelay(100*time.Millisecond),
+	)
+	_, _, err := bulkGeocode(t, client, input, &mapquest.BulkGeocodeOptions{Concurrency: 2, Checkpoint: checkpoint})
+	if !errors.Is(err, mapquest.ErrInvalidKey) {
+		t.Fatalf("got %v, want ErrInvalidKey", err)
+	}
+	if n := len(srv.Requests()); n != 2 {
+		t.Errorf("got %d requests, want 2", n)
+	}
+	if n := checkpointLines(t, checkpoint); n != 1 {
+		t.Errorf("checkpointed %d rows, want the one finished after the key error", n)
+	}
+
+	srv.Reset()
+	_, stats, err := bulkGeocode(t, client, input, &mapquest.BulkGeocodeOptions{Concurrency: 2, Checkpoint: checkpoint})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(srv.Requests()); n != 19 || stats.Resumed != 1 {
+		t.Errorf("got %d requests and stats %+v, want 19 requests and 1 resumed row", n, stats)
+	}
+}
@@ -0,0 +1,132 @@
+package mapquest
+
+import (
+	"container/list"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// Cache stores raw API responses by a canonical key derived from the
+// request. Implementations must be safe for concurrent use.
+type Cache interface {
+	// Get returns the response stored for key, if any.
+	Get(key string) ([]byte, bool)
+	// Set stores the response for key.
+	Set(key string, value []byte)
+}
+
+// CacheStats counts the lookups of a Client in its Cache.
+type CacheStats struct {
+	Hits   uint64
+	Misses uint64
+}
+
+// WithCache makes the client consult cache for geocoding and nominatim
+// lookups. Only successful responses are stored.
+func WithCache(cache Cache) Option {
+	return func(c *Client) {
+		c.cache = cache
+	}
+}
+
+// CacheStats returns the number of cache hits and misses of the client.
+func (c *Client) CacheStats() CacheStats {
+	return CacheStats{
+		Hits:   c.cacheHits.Load(),
+		Misses: c.cacheMisses.Load(),
+	}
+}
+
+// cacheKey derives the canonical cache key of a request for u. The API key
+// is left out, and the query parameters are sorted.
+func cacheKey(service Service, u *url.URL) string {
+	q := u.Query()
+	q.Del("key")
+	return string(service) + ":" + u.Path + "?" + q.Encode()
+}
+
+// MemoryCache is an in-memory Cache evicting the least recently used
+// entries once it is full. Entries expire after a fixed TTL.
+type MemoryCache struct {
+	size int
+	ttl  time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+}
+
+type memoryCacheEntry struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+// NewMemoryCache creates a MemoryCache holding up to size entries, each
+// for at most ttl. A zero ttl keeps entries until they are evicted.
+func NewMemoryCache(size int, ttl time.Duration) *MemoryCache {
+	if size < 1 {
+		size = 1
+	}
+	return &MemoryCache{
+		size:    size,
+		ttl:     ttl,
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+	}
+}
+
+// Get implements Cache.
+func (m *MemoryCache) Get(key string) ([]byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	el, ok := m.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := el.Value.(*memoryCacheEntry)
+	if !entry.expires.IsZero() && time.Now().After(entry.expires) {
+		m.lru.Remove(el)
+		delete(m.entries, key)
+		return nil, false
+	}
+
+	m.lru.MoveToFront(el)
+	return entry.value, true
+}
+
+// Set implements Cache.
+func (m *MemoryCache) Set(key string, value []byte) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var expires time.Time
+	if m.ttl > 0 {
+		expires = time.Now().Add(m.ttl)
+	}
+
+	if el, ok := m.entries[key]; ok {
+		entry := el.Value.(*memoryCacheEntry)
+		entry.value = value
+		entry.expires = expires
+		m.lru.MoveToFront(el)
+		return
+	}
+
+	m.entries[key] = m.lru.PushFront(&memoryCacheEntry{key: key, value: value, expires: expires})
+	for m.lru.Len() > m.size {
+		el := m.lru.Back()
+		m.lru.Remove(el)
+		delete(m.entries, el.Value.(*memoryCacheEntry).key)
+	}
+}
+
+// Len returns the number of entries in the cache, including expired
+// entries not evicted yet.
+func (m *MemoryCache) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.lru.Len()
+}
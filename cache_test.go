@@ -0,0 +1,38 @@
+package mapquest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cking/mapquest"
+	"github.com/cking/mapquest/mapquesttest"
+)
+
+func TestCacheSkipsFailures(t *testing.T) {
+	srv := mapquesttest.NewServer()
+	defer srv.Close()
+	client := srv.Client("key", mapquest.WithCache(mapquest.NewMemoryCache(10, time.Hour)))
+	srv.Enqueue(mapquesttest.PathNominatimReverse, mapquesttest.JSONResponse(map[string]string{"error": "Unable to geocode"}))
+
+	req := &mapquest.NominatimReverseRequest{Latitude: 40.053116, Longitude: -76.313603}
+	_, err := client.Nominatim().ReverseContext(context.Background(), req)
+	var apiErr *mapquest.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("got error %v, want an APIError", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.Nominatim().ReverseContext(context.Background(), req); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got, want := client.CacheStats(), (mapquest.CacheStats{Hits: 1, Misses: 2}); got != want {
+		t.Errorf("got cache stats %+v, want %+v", got, want)
+	}
+	if n := len(srv.RequestsTo(mapquesttest.PathNominatimReverse)); n != 2 {
+		t.Errorf("got %d requests, want 2", n)
+	}
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/cking/mapquest"
+)
+
+func runBulk(ctx context.Context, e *env, args []string) error {
+	var (
+		out         = e.flags.String("out", "", "file to write the geocoded rows to; standard output by default")
+		checkpoint  = e.flags.String("checkpoint", "", "file to record progress in, to resume interrupted runs; <out>.checkpoint by default")
+		concurrency = e.flags.Int("concurrency", mapquest.DefaultBulkConcurrency, "maximum number of requests in flight")
+		rate        = e.flags.Float64("rate", 0, "maximum number of requests per second; unlimited by default")
+		intlMode    = e.flags.String("intl", "", "international mode: AUTO, 5BOX or 1BOX")
+		cols        mapquest.BulkColumns
+	)
+	e.flags.StringVar(&cols.Location, "location-column", "", "column holding a single line address")
+	e.flags.StringVar(&cols.Address.Street, "street-column", "", "column holding the street of a structured address")
+	e.flags.StringVar(&cols.Address.City, "city-column", "", "column holding the city of a structured address")
+	e.flags.StringVar(&cols.Address.County, "county-column", "", "column holding the county of a structured address")
+	e.flags.StringVar(&cols.Address.State, "state-column", "", "column holding the state of a structured address")
+	e.flags.StringVar(&cols.Address.PostalCode, "postal-code-column", "", "column holding the postal code of a structured address")
+	e.flags.StringVar(&cols.Address.Country, "country-column", "", "column holding the country of a structured address")
+	if err := e.parse(args); err != nil {
+		return err
+	}
+	if len(e.args) > 1 || (cols.Location == "" && cols.Address == (mapquest.GeocodeAddress{})) {
+		e.flags.Usage()
+		return errUsage
+	}
+	if *checkpoint == "" && *out != "" {
+		*checkpoint = *out + ".checkpoint"
+	}
+
+	var options []mapquest.Option
+	if *rate > 0 {
+		options = append(options, mapquest.WithRateLimit(mapquest.ServiceGeocoding, mapquest.RateLimit{Rate: *rate}))
+	}
+	client, err := e.client(options...)
+	if err != nil {
+		return err
+	}
+
+	var r io.Reader = os.Stdin
+	if len(e.args) == 1 && e.args[0] != "-" {
+		f, err := os.Open(e.args[0])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	var w io.WriteCloser = os.Stdout
+	if *out != "" {
+		if w, err = os.Create(*out); err != nil {
+			return err
+		}
+	}
+
+	stats, err := client.Geocoding().BulkGeocode(ctx, r, w, &mapquest.BulkGeocodeOptions{
+		Columns:     cols,
+		Request:     mapquest.GeocodeAddressRequest{IntlMode: mapquest.IntlMode(strings.ToUpper(*intlMode))},
+		Concurrency: *concurrency,
+		Checkpoint:  *checkpoint,
+	})
+	if *out != "" {
+		if cerr := w.Close(); err == nil {
+			err = cerr
+		}
+	}
+	if stats == nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "%d rows: %d geocoded, %d resumed, %d failed\n", stats.Rows, stats.Geocoded, stats.Resumed, stats.Failed)
+	if err != nil && *checkpoint != "" {
+		return fmt.Errorf("%v; run again to resume from %s", err, *checkpoint)
+	}
+	return err
+}
@@ -0,0 +1,255 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/cking/mapquest"
+)
+
+func runGeocode(ctx context.Context, e *env, args []string) error {
+	var (
+		limit    = e.flags.Int("limit", 1, "maximum number of results")
+		intlMode = e.flags.String("intl", "", "international mode: AUTO, 5BOX or 1BOX")
+	)
+	addr := addressFlags(e)
+	if err := e.parse(args); err != nil {
+		return err
+	}
+
+	req := &mapquest.GeocodeAddressRequest{
+		Location: strings.Join(e.args, " "),
+		Limit:    *limit,
+		IntlMode: mapquest.IntlMode(strings.ToUpper(*intlMode)),
+	}
+	if *addr != (mapquest.GeocodeAddress{}) {
+		req.Address = addr
+	}
+	if req.Location == "" && req.Address == nil {
+		e.flags.Usage()
+		return errUsage
+	}
+
+	client, err := e.client()
+	if err != nil {
+		return err
+	}
+	res, err := client.Geocoding().AddressContext(ctx, req)
+	if err != nil {
+		return err
+	}
+	return writeGeocode(os.Stdout, e.output, res)
+}
+
+// addressFlags registers the flags of a structured address.
+func addressFlags(e *env) *mapquest.GeocodeAddress {
+	addr := new(mapquest.GeocodeAddress)
+	e.flags.StringVar(&addr.Street, "street", "", "street of a structured address")
+	e.flags.StringVar(&addr.City, "city", "", "city of a structured address")
+	e.flags.StringVar(&addr.County, "county", "", "county of a structured address")
+	e.flags.StringVar(&addr.State, "state", "", "state of a structured address")
+	e.flags.StringVar(&addr.PostalCode, "postal-code", "", "postal code of a structured address")
+	e.flags.StringVar(&addr.Country, "country", "", "country of a structured address")
+	return addr
+}
+
+func runReverse(ctx context.Context, e *env, args []string) error {
+	if err := e.parse(args); err != nil {
+		return err
+	}
+	p, err := parsePoint(e)
+	if err != nil {
+		return err
+	}
+
+	client, err := e.client()
+	if err != nil {
+		return err
+	}
+	res, err := client.Geocoding().ReverseContext(ctx, &mapquest.GeocodeReverseRequest{Location: p})
+	if err != nil {
+		return err
+	}
+	return writeGeocode(os.Stdout, e.output, res)
+}
+
+func runSearch(ctx context.Context, e *env, args []string) error {
+	var (
+		limit     = e.flags.Int("limit", 10, "maximum number of results")
+		countries = e.flags.String("countries", "", "comma separated country codes to limit the search to")
+	)
+	if err := e.parse(args); err != nil {
+		return err
+	}
+
+	req := &mapquest.NominatimSearchRequest{
+		Query:          strings.Join(e.args, " "),
+		AddressDetails: true,
+		Limit:          *limit,
+	}
+	if *countries != "" {
+		req.CountryCodes = strings.Split(*countries, ",")
+	}
+	if req.Query == "" {
+		e.flags.Usage()
+		return errUsage
+	}
+
+	client, err := e.client()
+	if err != nil {
+		return err
+	}
+	res, err := client.Nominatim().SearchContext(ctx, req)
+	if err != nil {
+		return err
+	}
+	return writeNominatim(os.Stdout, e.output, res)
+}
+
+func runNominatimReverse(ctx context.Context, e *env, args []string) error {
+	if err := e.parse(args); err != nil {
+		return err
+	}
+	p, err := parsePoint(e)
+	if err != nil {
+		return err
+	}
+
+	client, err := e.client()
+	if err != nil {
+		return err
+	}
+	entry, err := client.Nominatim().ReverseContext(ctx, &mapquest.NominatimReverseRequest{Latitude: p.Latitude, Longitude: p.Longitude})
+	if err != nil {
+		return err
+	}
+	return writeNominatim(os.Stdout, e.output, &mapquest.NominatimSearchResponse{Results: []*mapquest.NominatimSearchResponseEntry{entry}})
+}
+
+func runStaticMap(ctx context.Context, e *env, args []string) error {
+	var (
+		out     = e.flags.String("out", "", "file to write the image to")
+		center  = e.flags.String("center", "", "center of the map, as address or lat,lng")
+		zoom    = e.flags.Int("zoom", 0, "zoom level from 1 to 20")
+		size    = e.flags.String("size", "600x400", "size of the image in pixels, as WIDTHxHEIGHT")
+		retina  = e.flags.Bool("retina", false, "render at twice the resolution")
+		typ     = e.flags.String("type", "", "map type: map, hyb, sat, light or dark")
+		format  = e.flags.String("format", "", "image format: png, gif, jpeg, jpg, jpg70, jpg80 or jpg90; derived from -out by default")
+		markers = e.flags.String("locations", "", "locations to mark, separated by ||")
+		session = e.flags.String("session", "", "session ID of a route to draw")
+	)
+	if err := e.parse(args); err != nil {
+		return err
+	}
+	if *out == "" {
+		e.flags.Usage()
+		return errUsage
+	}
+
+	var width, height int
+	if _, err := fmt.Sscanf(*size, "%dx%d", &width, &height); err != nil {
+		return fmt.Errorf("invalid size %q, want WIDTHxHEIGHT", *size)
+	}
+	imageFormat, err := staticMapFormat(*format, *out)
+	if err != nil {
+		return err
+	}
+
+	req := &mapquest.StaticMapRequest{
+		Size:    &mapquest.StaticMapSize{Width: width, Height: height, Retina: *retina},
+		Center:  *center,
+		Zoom:    *zoom,
+		Type:    mapquest.StaticMapType(*typ),
+		Format:  imageFormat,
+		Session: *session,
+	}
+	if *markers != "" {
+		for _, l := range strings.Split(*markers, "||") {
+			req.Locations = append(req.Locations, mapquest.StaticMapLocation{Location: l})
+		}
+	}
+
+	client, err := e.client()
+	if err != nil {
+		return err
+	}
+	reader, err := client.StaticMap().MapReaderContext(ctx, req)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	f, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, reader); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// staticMapFormats are the image formats the static map API renders.
+var staticMapFormats = []mapquest.StaticMapFormat{
+	mapquest.StaticMapFormatPNG,
+	mapquest.StaticMapFormatGIF,
+	mapquest.StaticMapFormatJPEG,
+	mapquest.StaticMapFormatJPG,
+	mapquest.StaticMapFormatJPG70,
+	mapquest.StaticMapFormatJPG80,
+	mapquest.StaticMapFormatJPG90,
+}
+
+// staticMapFormat returns the image format given by the -format flag or,
+// if unset, by the extension of out. Files without an extension get the
+// default format of the API.
+func staticMapFormat(format, out string) (mapquest.StaticMapFormat, error) {
+	source := "-format"
+	if format == "" {
+		format = strings.TrimPrefix(filepath.Ext(out), ".")
+		source = "the extension of " + out
+	}
+	if format == "" {
+		return "", nil
+	}
+
+	for _, f := range staticMapFormats {
+		if strings.EqualFold(format, string(f)) {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported image format %q from %s, want one of %v", format, source, staticMapFormats)
+}
+
+// parsePoint parses the coordinate given as "lat lng" or "lat,lng" in
+// the positional arguments.
+func parsePoint(e *env) (*mapquest.GeoPoint, error) {
+	args := e.args
+	if len(args) == 1 {
+		args = strings.Split(args[0], ",")
+	}
+	if len(args) != 2 {
+		e.flags.Usage()
+		return nil, errUsage
+	}
+
+	lat, err := strconv.ParseFloat(strings.TrimSpace(args[0]), 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid latitude %q", args[0])
+	}
+	lng, err := strconv.ParseFloat(strings.TrimSpace(args[1]), 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid longitude %q", args[1])
+	}
+	return &mapquest.GeoPoint{Latitude: lat, Longitude: lng}, nil
+}
+
+// errUsage is returned after printing the usage of a command.
+var errUsage = errors.New("invalid arguments")
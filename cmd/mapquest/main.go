@@ -0,0 +1,199 @@
+// Command mapquest is a command-line client for the MapQuest APIs.
+//
+// Usage:
+//
+//	mapquest <command> [flags] [arguments]
+//
+// The commands are:
+//
+//	geocode            geocode an address
+//	reverse            look up the address of a coordinate
+//	search             search OpenStreetMap data via Nominatim
+//	nominatim-reverse  look up the place at a coordinate via Nominatim
+//	staticmap          render a static map to a file
+//	bulk               geocode the rows of a CSV file
+//
+// The access key is taken from the -key flag, the MAPQUEST_KEY environment
+// variable, or the ACCESS_KEY file in the current directory, in that order.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cking/mapquest"
+)
+
+// keyEnv is the environment variable holding the access key.
+const keyEnv = "MAPQUEST_KEY"
+
+// keyFile is the file holding the access key, as used by the tests.
+const keyFile = "ACCESS_KEY"
+
+type command struct {
+	name    string
+	usage   string
+	summary string
+	run     func(ctx context.Context, env *env, args []string) error
+}
+
+var commands []*command
+
+func init() {
+	commands = []*command{
+		{"geocode", "geocode [flags] <address>", "geocode an address", runGeocode},
+		{"reverse", "reverse [flags] <lat> <lng>", "look up the address of a coordinate", runReverse},
+		{"search", "search [flags] <query>", "search OpenStreetMap data via Nominatim", runSearch},
+		{"nominatim-reverse", "nominatim-reverse [flags] <lat> <lng>", "look up the place at a coordinate via Nominatim", runNominatimReverse},
+		{"staticmap", "staticmap [flags] -out <file>", "render a static map to a file", runStaticMap},
+		{"bulk", "bulk [flags] [input.csv]", "geocode the rows of a CSV file", runBulk},
+	}
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	name := os.Args[1]
+	for _, cmd := range commands {
+		if cmd.name != name {
+			continue
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		err := cmd.exec(ctx, os.Args[2:])
+		stop()
+		if errors.Is(err, flag.ErrHelp) || errors.Is(err, errUsage) {
+			os.Exit(2)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "mapquest %s: %v\n", name, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if name != "help" && name != "-h" && name != "-help" {
+		fmt.Fprintf(os.Stderr, "mapquest: unknown command %q\n", name)
+	}
+	usage()
+	os.Exit(2)
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: mapquest <command> [flags] [arguments]\n\nCommands:\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-18s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintf(os.Stderr, "\nRun 'mapquest <command> -h' for the flags of a command.\n")
+}
+
+// env holds the settings shared by all commands.
+type env struct {
+	flags   *flag.FlagSet
+	args    []string
+	key     string
+	output  string
+	baseURL string
+	timeout time.Duration
+}
+
+// parse parses the flags of a command. Unlike flag.FlagSet.Parse, it
+// accepts flags after positional arguments, and takes negative numbers
+// like coordinates as positional arguments.
+func (e *env) parse(args []string) error {
+	var flags []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			e.args = append(e.args, args[i+1:]...)
+			break
+		}
+		if !strings.HasPrefix(arg, "-") || arg == "-" || isNegativeNumber(arg) {
+			e.args = append(e.args, arg)
+			continue
+		}
+
+		flags = append(flags, arg)
+		name := strings.TrimLeft(arg, "-")
+		if strings.Contains(name, "=") {
+			continue
+		}
+		// pass the value of non-boolean flags along
+		if f := e.flags.Lookup(name); f != nil && !isBoolFlag(f) && i+1 < len(args) {
+			i++
+			flags = append(flags, args[i])
+		}
+	}
+
+	return e.flags.Parse(flags)
+}
+
+func isBoolFlag(f *flag.Flag) bool {
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+func isNegativeNumber(arg string) bool {
+	_, err := strconv.ParseFloat(arg, 64)
+	return err == nil && strings.HasPrefix(arg, "-")
+}
+
+// client creates a client from the shared settings and options.
+func (e *env) client(options ...mapquest.Option) (*mapquest.Client, error) {
+	key, err := e.resolveKey()
+	if err != nil {
+		return nil, err
+	}
+
+	options = append([]mapquest.Option{mapquest.WithTimeout(e.timeout)}, options...)
+	if e.baseURL != "" {
+		u, err := url.Parse(e.baseURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid base URL: %v", err)
+		}
+		options = append(options, mapquest.WithBaseURL(u))
+	}
+	return mapquest.NewClient(key, options...), nil
+}
+
+// resolveKey returns the key from the flag, the environment or the key
+// file, in that order.
+func (e *env) resolveKey() (string, error) {
+	if e.key != "" {
+		return e.key, nil
+	}
+	if key := os.Getenv(keyEnv); key != "" {
+		return key, nil
+	}
+	data, err := os.ReadFile(keyFile)
+	if err == nil && strings.TrimSpace(string(data)) != "" {
+		return strings.TrimSpace(string(data)), nil
+	}
+	return "", fmt.Errorf("no access key: use -key, set %s or create an %s file", keyEnv, keyFile)
+}
+
+func (cmd *command) exec(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	e := &env{flags: fs}
+	fs.StringVar(&e.key, "key", "", "MapQuest access key")
+	fs.StringVar(&e.output, "o", "json", "output format: json, table or geojson")
+	fs.StringVar(&e.baseURL, "base-url", "", "base URL of the API, e.g. https://www.mapquestapi.com")
+	fs.DurationVar(&e.timeout, "timeout", 30*time.Second, "request timeout")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: mapquest %s\n\n%s.\n\nFlags:\n", cmd.usage, strings.ToUpper(cmd.summary[:1])+cmd.summary[1:])
+		fs.PrintDefaults()
+	}
+
+	return cmd.run(ctx, e, args)
+}
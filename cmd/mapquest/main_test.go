@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cking/mapquest"
+	"github.com/cking/mapquest/mapquesttest"
+)
+
+// chdir changes into dir for the rest of the test.
+func chdir(t *testing.T, dir string) {
+	t.Helper()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+}
+
+func TestResolveKey(t *testing.T) {
+	chdir(t, t.TempDir())
+	t.Setenv(keyEnv, "")
+
+	e := new(env)
+	if _, err := e.resolveKey(); err == nil {
+		t.Error("resolved a key without any source")
+	}
+
+	if err := os.WriteFile(keyFile, []byte("file-key\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if key, err := e.resolveKey(); err != nil || key != "file-key" {
+		t.Errorf("key file: got %q, %v", key, err)
+	}
+
+	t.Setenv(keyEnv, "env-key")
+	if key, err := e.resolveKey(); err != nil || key != "env-key" {
+		t.Errorf("environment: got %q, %v, want the environment over the key file", key, err)
+	}
+
+	e.key = "flag-key"
+	if key, err := e.resolveKey(); err != nil || key != "flag-key" {
+		t.Errorf("flag: got %q, %v, want the flag over the environment", key, err)
+	}
+}
+
+func TestStaticMapFormat(t *testing.T) {
+	tests := []struct {
+		format, out string
+		want        mapquest.StaticMapFormat
+		wantErr     bool
+	}{
+		{"", "map.png", mapquest.StaticMapFormatPNG, false},
+		{"", "map.JPG", mapquest.StaticMapFormatJPG, false},
+		{"", "map", "", false},
+		{"", "map.txt", "", true},
+		{"jpg80", "map.txt", mapquest.StaticMapFormatJPG80, false},
+		{"GIF", "map.png", mapquest.StaticMapFormatGIF, false},
+		{"webp", "map.webp", "", true},
+	}
+	for _, tt := range tests {
+		got, err := staticMapFormat(tt.format, tt.out)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("staticMapFormat(%q, %q) = %q, %v, want %q", tt.format, tt.out, got, err, tt.want)
+		}
+	}
+}
+
+func TestStaticMapCommand(t *testing.T) {
+	srv := mapquesttest.NewServer()
+	defer srv.Close()
+	dir := t.TempDir()
+	var staticmap *command
+	for _, cmd := range commands {
+		if cmd.name == "staticmap" {
+			staticmap = cmd
+		}
+	}
+
+	err := staticmap.exec(context.Background(), []string{"-key", "key", "-base-url", srv.URL, "-center", "Lancaster, PA", "-out", filepath.Join(dir, "map.txt")})
+	if err == nil || !strings.Contains(err.Error(), `unsupported image format "txt"`) {
+		t.Errorf("got %v, want an unsupported image format", err)
+	}
+	if n := len(srv.Requests()); n != 0 {
+		t.Errorf("got %d requests, want none", n)
+	}
+
+	out := filepath.Join(dir, "map.jpg")
+	if err := staticmap.exec(context.Background(), []string{"-key", "key", "-base-url", srv.URL, "-center", "Lancaster, PA", "-out", out}); err != nil {
+		t.Fatal(err)
+	}
+	if got := srv.RequestsTo(mapquesttest.PathStaticMap)[0].Query.Get("format"); got != "jpg" {
+		t.Errorf("got format %q, want jpg", got)
+	}
+	if _, err := os.Stat(out); err != nil {
+		t.Error(err)
+	}
+}
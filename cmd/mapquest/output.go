@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/cking/mapquest"
+)
+
+// writeJSON writes v as indented JSON.
+func writeJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func writeGeocode(w io.Writer, format string, res *mapquest.GeocodeAddressResponse) error {
+	switch format {
+	case "json":
+		return writeJSON(w, res)
+	case "geojson":
+		return writeJSON(w, res.ToFeatureCollection())
+	case "table":
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "RESULT\tLAT\tLNG\tQUALITY\tSTREET\tCITY\tSTATE\tPOSTAL CODE\tCOUNTRY")
+		for i, result := range res.Results {
+			for _, l := range result.Locations {
+				var lat, lng string
+				if l.LatLong != nil {
+					lat, lng = formatCoord(l.LatLong.Latitude), formatCoord(l.LatLong.Longitude)
+				}
+				fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+					i, lat, lng, l.GeocodeQualityCode, l.Street, l.AdminArea5, l.AdminArea3, l.PostalCode, l.AdminArea1)
+			}
+		}
+		return tw.Flush()
+	}
+	return fmt.Errorf("unknown output format %q", format)
+}
+
+func writeNominatim(w io.Writer, format string, res *mapquest.NominatimSearchResponse) error {
+	switch format {
+	case "json":
+		return writeJSON(w, res.Results)
+	case "geojson":
+		return writeJSON(w, res.ToFeatureCollection())
+	case "table":
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "LAT\tLNG\tOSM\tTYPE\tIMPORTANCE\tNAME")
+		for _, e := range res.Results {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%.3f\t%s\n",
+				formatCoord(e.Latitude), formatCoord(e.Longitude),
+				strings.TrimSpace(e.OSMType+" "+e.OSMId), strings.Trim(e.Class+"/"+e.Type, "/"),
+				e.Importance, e.DisplayName)
+		}
+		return tw.Flush()
+	}
+	return fmt.Errorf("unknown output format %q", format)
+}
+
+func formatCoord(v float64) string {
+	return fmt.Sprintf("%.6f", v)
+}
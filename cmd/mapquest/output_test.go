@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cking/mapquest"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+// golden compares got to the file testdata/name, or updates the file with
+// -update.
+func golden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s: got\n%s\nwant\n%s", name, got, want)
+	}
+}
+
+func TestWriteGeocode(t *testing.T) {
+	res := &mapquest.GeocodeAddressResponse{
+		Info: &mapquest.ResponseInfo{},
+		Results: []*mapquest.GeocodeAddressResponseEntry{{
+			ProvidedLocation: &mapquest.GeocodeProvidedLocation{Location: "1090 N Charlotte St, Lancaster, PA"},
+			Locations: []*mapquest.GeocodeAddressResponseLocationEntry{{
+				LatLong:            &mapquest.GeoPoint{Latitude: 40.053116, Longitude: -76.313603},
+				Street:             "1090 N Charlotte St",
+				PostalCode:         "17603",
+				AdminArea5:         "Lancaster",
+				AdminArea3:         "PA",
+				AdminArea1:         "US",
+				GeocodeQualityCode: "P1AAA",
+			}},
+		}},
+	}
+
+	for _, format := range []string{"json", "table", "geojson"} {
+		var buf bytes.Buffer
+		if err := writeGeocode(&buf, format, res); err != nil {
+			t.Fatal(err)
+		}
+		golden(t, "geocode."+format, buf.Bytes())
+	}
+	if err := writeGeocode(new(bytes.Buffer), "xml", res); err == nil {
+		t.Error("wrote an unknown output format")
+	}
+}
+
+func TestWriteNominatim(t *testing.T) {
+	res := &mapquest.NominatimSearchResponse{Results: []*mapquest.NominatimSearchResponseEntry{{
+		BoundingBox: mapquest.NominatimBoundingBox{52.5160, 52.5175, 13.3770, 13.3790},
+		Class:       "tourism",
+		DisplayName: "Brandenburger Tor, Pariser Platz, Mitte, Berlin, 10117, Deutschland",
+		Importance:  0.853,
+		Latitude:    52.5162746,
+		Longitude:   13.3777041,
+		OSMId:       "518071791",
+		OSMType:     "way",
+		PlaceID:     "172330218",
+		Type:        "attraction",
+	}}}
+
+	for _, format := range []string{"json", "table", "geojson"} {
+		var buf bytes.Buffer
+		if err := writeNominatim(&buf, format, res); err != nil {
+			t.Fatal(err)
+		}
+		golden(t, "nominatim."+format, buf.Bytes())
+	}
+}
@@ -0,0 +1,202 @@
+package mapquest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	ElevationPrefix  = "elevation"
+	ElevationVersion = "v1"
+
+	// elevationCompressThreshold is the number of points from which on
+	// paths are sent in the compressed shape format.
+	elevationCompressThreshold = 20
+)
+
+// ErrElevationPath is returned for elevation requests not giving exactly
+// one of a path or a route session ID.
+var ErrElevationPath = errors.New("elevation request needs either points or a session ID")
+
+// ElevationAPI enables users to request elevation profiles and charts via
+// the MapQuest API.
+// See https://developer.mapquest.com/documentation/open/elevation-api/ for details.
+type ElevationAPI struct {
+	c *Client
+}
+
+// Profile returns the elevation along the path given in req.
+func (api *ElevationAPI) Profile(req *ElevationRequest) (*ElevationResponse, error) {
+	return api.ProfileContext(context.Background(), req)
+}
+
+// ProfileContext is like Profile, but binds the request to ctx.
+func (api *ElevationAPI) ProfileContext(ctx context.Context, req *ElevationRequest) (*ElevationResponse, error) {
+	q, err := req.values()
+	if err != nil {
+		return nil, err
+	}
+
+	q.Set("key", api.c.key)
+	q.Set("outFormat", "json")
+	u := api.c.apiURL(ServiceElevation, ElevationVersion, "profile")
+	u.RawQuery = q.Encode()
+
+	res := new(ElevationResponse)
+	if err := api.c.getJSON(ctx, ServiceElevation, u, res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// Chart fetches a chart of the elevation along the path given in req and
+// decodes it.
+func (api *ElevationAPI) Chart(req *ElevationChartRequest) (image.Image, error) {
+	return api.ChartContext(context.Background(), req)
+}
+
+// ChartContext is like Chart, but binds the request to ctx.
+func (api *ElevationAPI) ChartContext(ctx context.Context, req *ElevationChartRequest) (image.Image, error) {
+	reader, err := api.ChartReaderContext(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	img, _, err := image.Decode(reader)
+	return img, err
+}
+
+// ChartReader fetches a chart of the elevation along the path given in
+// req. The caller is responsible for closing the returned reader.
+func (api *ElevationAPI) ChartReader(req *ElevationChartRequest) (io.ReadCloser, error) {
+	return api.ChartReaderContext(context.Background(), req)
+}
+
+// ChartReaderContext is like ChartReader, but binds the request to ctx.
+func (api *ElevationAPI) ChartReaderContext(ctx context.Context, req *ElevationChartRequest) (io.ReadCloser, error) {
+	q, err := req.values()
+	if err != nil {
+		return nil, err
+	}
+	if req.Width > 0 {
+		q.Set("width", strconv.Itoa(req.Width))
+	}
+	if req.Height > 0 {
+		q.Set("height", strconv.Itoa(req.Height))
+	}
+
+	q.Set("key", api.c.key)
+	u := api.c.apiURL(ServiceElevation, ElevationVersion, "chart")
+	u.RawQuery = q.Encode()
+
+	return api.c.getImage(ctx, ServiceElevation, u)
+}
+
+type ElevationUnit string
+
+const (
+	ElevationUnitMeters ElevationUnit = "m"
+	ElevationUnitFeet   ElevationUnit = "f"
+)
+
+// ElevationRequest describes a path either by its points or by the
+// session ID of a route calculated before. Set exactly one of them.
+type ElevationRequest struct {
+	Points    []GeoPoint
+	SessionID string
+	Unit      ElevationUnit
+}
+
+func (req *ElevationRequest) values() (url.Values, error) {
+	if (len(req.Points) == 0) == (req.SessionID == "") {
+		return nil, ErrElevationPath
+	}
+
+	q := make(url.Values)
+	if req.SessionID != "" {
+		q.Set("sessionId", req.SessionID)
+	} else if len(req.Points) >= elevationCompressThreshold {
+		q.Set("shapeFormat", string(ShapeFormatCmp6))
+		q.Set("latLngCollection", EncodeCompressedShape(req.Points, ShapeFormatCmp6.Precision()))
+	} else {
+		coords := make([]string, 0, 2*len(req.Points))
+		for _, p := range req.Points {
+			coords = append(coords,
+				strconv.FormatFloat(p.Latitude, 'f', -1, 64),
+				strconv.FormatFloat(p.Longitude, 'f', -1, 64))
+		}
+		q.Set("shapeFormat", string(ShapeFormatRaw))
+		q.Set("latLngCollection", strings.Join(coords, ","))
+	}
+	if req.Unit != "" {
+		q.Set("unit", string(req.Unit))
+	}
+
+	return q, nil
+}
+
+type ElevationChartRequest struct {
+	ElevationRequest
+	Width  int
+	Height int
+}
+
+type ElevationResponse struct {
+	Info    *ResponseInfo
+	Profile []ElevationSample
+}
+
+func (res *ElevationResponse) responseInfo() *ResponseInfo {
+	return res.Info
+}
+
+// ElevationSample is the elevation at a point of the path.
+type ElevationSample struct {
+	// Distance is the distance from the start of the path, in kilometers
+	// or miles depending on the unit.
+	Distance float64
+	// Height is the elevation in meters or feet. It is
+	// ElevationNoData if the elevation at Point is unknown.
+	Height float64
+	Point  GeoPoint
+}
+
+// ElevationNoData is the height of points without elevation data.
+const ElevationNoData = -32768
+
+func (res *ElevationResponse) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Info             *ResponseInfo `json:"info"`
+		ElevationProfile []struct {
+			Distance float64 `json:"distance"`
+			Height   float64 `json:"height"`
+		} `json:"elevationProfile"`
+		ShapePoints []float64 `json:"shapePoints"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	res.Info = raw.Info
+	if n := len(raw.ShapePoints); n != 2*len(raw.ElevationProfile) && n > 0 {
+		return fmt.Errorf("elevation: %d shape points for %d samples", n/2, len(raw.ElevationProfile))
+	}
+	res.Profile = make([]ElevationSample, len(raw.ElevationProfile))
+	for i, s := range raw.ElevationProfile {
+		res.Profile[i].Distance = s.Distance
+		res.Profile[i].Height = s.Height
+		if len(raw.ShapePoints) > 0 {
+			res.Profile[i].Point = GeoPoint{Latitude: raw.ShapePoints[2*i], Longitude: raw.ShapePoints[2*i+1]}
+		}
+	}
+	return nil
+}
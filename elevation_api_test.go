@@ -0,0 +1,118 @@
+package mapquest_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/cking/mapquest"
+	"github.com/cking/mapquest/mapquesttest"
+)
+
+const pathElevationProfile = "/elevation/v1/profile"
+
+func TestElevationProfile(t *testing.T) {
+	srv := mapquesttest.NewServer()
+	defer srv.Close()
+	srv.Enqueue(pathElevationProfile, &mapquesttest.Response{Body: []byte(`{
+		"info": {"statuscode": 0, "messages": []},
+		"shapePoints": [39.74012, -104.9849, 39.7995, -105.7237, 39.6404, -106.3741],
+		"elevationProfile": [
+			{"distance": 0, "height": 1616},
+			{"distance": 63.406, "height": 3467},
+			{"distance": 122.1, "height": -32768}
+		]
+	}`)})
+
+	points := []mapquest.GeoPoint{{Latitude: 39.74012, Longitude: -104.9849}, {Latitude: 39.6404, Longitude: -106.3741}}
+	res, err := srv.Client("key").Elevation().ProfileContext(context.Background(), &mapquest.ElevationRequest{Points: points})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []mapquest.ElevationSample{
+		{Distance: 0, Height: 1616, Point: mapquest.GeoPoint{Latitude: 39.74012, Longitude: -104.9849}},
+		{Distance: 63.406, Height: 3467, Point: mapquest.GeoPoint{Latitude: 39.7995, Longitude: -105.7237}},
+		{Distance: 122.1, Height: mapquest.ElevationNoData, Point: mapquest.GeoPoint{Latitude: 39.6404, Longitude: -106.3741}},
+	}
+	if len(res.Profile) != len(want) {
+		t.Fatalf("got %d samples, want %d", len(res.Profile), len(want))
+	}
+	for i := range want {
+		if res.Profile[i] != want[i] {
+			t.Errorf("sample %d: got %+v, want %+v", i, res.Profile[i], want[i])
+		}
+	}
+
+	q := srv.RequestsTo(pathElevationProfile)[0].Query
+	if got, want := q.Get("latLngCollection"), "39.74012,-104.9849,39.6404,-106.3741"; q.Get("shapeFormat") != "raw" || got != want {
+		t.Errorf("got %s path %q, want raw path %q", q.Get("shapeFormat"), got, want)
+	}
+}
+
+func TestElevationProfileMismatch(t *testing.T) {
+	srv := mapquesttest.NewServer()
+	defer srv.Close()
+	srv.Enqueue(pathElevationProfile, &mapquesttest.Response{Body: []byte(`{
+		"info": {"statuscode": 0},
+		"shapePoints": [39.74012, -104.9849],
+		"elevationProfile": [{"distance": 0, "height": 1616}, {"distance": 1, "height": 1620}]
+	}`)})
+
+	_, err := srv.Client("key").Elevation().ProfileContext(context.Background(), &mapquest.ElevationRequest{SessionID: "5f9b1a2c"})
+	if err == nil || !strings.Contains(err.Error(), "1 shape points for 2 samples") {
+		t.Errorf("got %v, want a mismatch error", err)
+	}
+	if got := srv.RequestsTo(pathElevationProfile)[0].Query.Get("sessionId"); got != "5f9b1a2c" {
+		t.Errorf("got session %q", got)
+	}
+}
+
+func TestElevationCompressedPath(t *testing.T) {
+	srv := mapquesttest.NewServer()
+	defer srv.Close()
+	client := srv.Client("key")
+
+	for _, n := range []int{19, 20} {
+		points := make([]mapquest.GeoPoint, n)
+		for i := range points {
+			points[i] = mapquest.GeoPoint{Latitude: 39.74 + float64(i)*0.01, Longitude: -104.98 - float64(i)*0.01}
+		}
+		srv.Reset()
+		srv.Enqueue(pathElevationProfile, &mapquesttest.Response{Body: []byte(`{"info":{"statuscode":0}}`)})
+		if _, err := client.Elevation().ProfileContext(context.Background(), &mapquest.ElevationRequest{Points: points}); err != nil {
+			t.Fatal(err)
+		}
+
+		q := srv.RequestsTo(pathElevationProfile)[0].Query
+		if n < 20 {
+			if q.Get("shapeFormat") != "raw" {
+				t.Errorf("%d points: got shape format %q, want raw", n, q.Get("shapeFormat"))
+			}
+			continue
+		}
+		if q.Get("shapeFormat") != "cmp6" {
+			t.Fatalf("%d points: got shape format %q, want cmp6", n, q.Get("shapeFormat"))
+		}
+		decoded, err := mapquest.DecodeCompressedShape(q.Get("latLngCollection"), 6)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(decoded) != n {
+			t.Fatalf("%d points: decoded %d", n, len(decoded))
+		}
+		for i, p := range points {
+			if math.Abs(decoded[i].Latitude-p.Latitude) > 1e-6 || math.Abs(decoded[i].Longitude-p.Longitude) > 1e-6 {
+				t.Errorf("point %d decoded as %v, want %v", i, decoded[i], p)
+			}
+		}
+	}
+
+	for _, req := range []*mapquest.ElevationRequest{{}, {Points: []mapquest.GeoPoint{mapquesttest.DefaultPoint}, SessionID: "5f9b1a2c"}} {
+		if _, err := client.Elevation().ProfileContext(context.Background(), req); !errors.Is(err, mapquest.ErrElevationPath) {
+			t.Errorf("got %v, want ErrElevationPath", err)
+		}
+	}
+}
@@ -1,7 +1,210 @@
 package mapquest
 
-import "errors"
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
 
 var (
 	ErrDimensionToLarge = errors.New("dimenstion to large")
+
+	// ErrBadRequest is matched by API errors caused by invalid or
+	// missing request parameters.
+	ErrBadRequest = errors.New("bad request")
+	// ErrInvalidKey is matched by API errors caused by a missing, invalid
+	// or unauthorized key.
+	ErrInvalidKey = errors.New("invalid key")
+	// ErrQuotaExceeded is matched by API errors caused by exceeding the
+	// transaction quota or request rate of a key.
+	ErrQuotaExceeded = errors.New("quota exceeded")
+	// ErrServerError is matched by API errors caused by a failure on the
+	// MapQuest side.
+	ErrServerError = errors.New("server error")
+	// ErrRouteNotFound is matched by API errors of the routing APIs if no
+	// route could be found between the locations.
+	ErrRouteNotFound = errors.New("route not found")
+
+	// ErrLocationConflict is returned for requests setting both a single
+	// line and a structured address, or a coordinate along with either.
+	ErrLocationConflict = errors.New("location and address are mutually exclusive")
+	// ErrInvalidIntlMode is returned for requests with an unknown intlMode,
+	// or a location the intlMode does not accept.
+	ErrInvalidIntlMode = errors.New("invalid intlMode")
+
+	// ErrStaticMapRouteConflict is returned for static map requests giving
+	// both a route session and a start or end.
+	ErrStaticMapRouteConflict = errors.New("route session and start/end are mutually exclusive")
+	// ErrStaticMapRouteIncomplete is returned for static map requests
+	// giving only one of start and end.
+	ErrStaticMapRouteIncomplete = errors.New("route needs both start and end")
 )
+
+// requestIDHeaders lists the response headers MapQuest (or its CDN) uses
+// to identify a request, in order of preference.
+var requestIDHeaders = []string{"X-Request-Id", "X-Amzn-Requestid", "X-Amz-Cf-Id"}
+
+// APIError is returned by all API calls when MapQuest rejects a request,
+// either through the HTTP status or through the status code in the info
+// block of the response. Use errors.Is with ErrBadRequest, ErrInvalidKey,
+// ErrQuotaExceeded, ErrServerError or ErrRouteNotFound to branch on the
+// cause.
+type APIError struct {
+	// HTTPStatus is the HTTP status code of the response.
+	HTTPStatus int
+	// StatusCode is the MapQuest status code of the info block, if any.
+	// See https://developer.mapquest.com/documentation/geocoding-api/status-codes
+	StatusCode int
+	// Messages are the messages of the info block, or the response body
+	// if it did not contain an info block.
+	Messages []string
+	// Endpoint is the path of the endpoint that was called.
+	Endpoint string
+	// RequestID identifies the request, if the response carried an ID.
+	RequestID string
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("mapquest: %s: HTTP %d", e.Endpoint, e.HTTPStatus)
+	if e.StatusCode != 0 {
+		msg += fmt.Sprintf(", status %d", e.StatusCode)
+	}
+	if len(e.Messages) > 0 {
+		msg += ": " + strings.Join(e.Messages, "; ")
+	}
+	if e.RequestID != "" {
+		msg += " (request " + e.RequestID + ")"
+	}
+	return msg
+}
+
+// Is reports whether target is the sentinel error describing the cause
+// of e.
+func (e *APIError) Is(target error) bool {
+	cause := e.cause()
+	return cause != nil && cause == target
+}
+
+func (e *APIError) cause() error {
+	if e.HTTPStatus == http.StatusTooManyRequests {
+		return ErrQuotaExceeded
+	}
+
+	code := e.StatusCode
+	if code == 0 {
+		code = e.HTTPStatus
+	}
+
+	switch {
+	case code == http.StatusBadRequest:
+		return ErrBadRequest
+	case e.StatusCode == 402:
+		// the routing APIs report unroutable locations with 402
+		return ErrRouteNotFound
+	case code == http.StatusUnauthorized || code == http.StatusForbidden:
+		for _, m := range e.Messages {
+			m = strings.ToLower(m)
+			if strings.Contains(m, "quota") || strings.Contains(m, "exceeded") || strings.Contains(m, "limit") {
+				return ErrQuotaExceeded
+			}
+		}
+		return ErrInvalidKey
+	case code >= 500:
+		return ErrServerError
+	}
+
+	return nil
+}
+
+// responseInfoer is implemented by responses carrying a MapQuest info block.
+type responseInfoer interface {
+	responseInfo() *ResponseInfo
+}
+
+// checkResponseInfo returns an *APIError if the info block of res reports
+// a failure.
+func checkResponseInfo(httpResponse *http.Response, res responseInfoer) error {
+	info := res.responseInfo()
+	if info == nil || info.StatusCode == 0 {
+		return nil
+	}
+
+	err := newAPIError(httpResponse)
+	err.StatusCode = info.StatusCode
+	err.Messages = info.Messages
+	return err
+}
+
+// responseErrorer is implemented by responses reporting a failure as an
+// error message instead of an info block, like Nominatim does.
+type responseErrorer interface {
+	responseError() string
+}
+
+// checkResponseBody returns an *APIError if the decoded response v reports
+// a failure, either in its info block or as an error message.
+func checkResponseBody(httpResponse *http.Response, v interface{}) error {
+	if res, ok := v.(responseInfoer); ok {
+		if err := checkResponseInfo(httpResponse, res); err != nil {
+			return err
+		}
+	}
+	if res, ok := v.(responseErrorer); ok {
+		if msg := res.responseError(); msg != "" {
+			err := newAPIError(httpResponse)
+			err.Messages = []string{msg}
+			return err
+		}
+	}
+	return nil
+}
+
+// maxErrorBodySize limits how much of an error response is read.
+const maxErrorBodySize = 64 << 10
+
+// checkResponse returns an *APIError if httpResponse does not indicate
+// success. The body is consumed in that case.
+func checkResponse(httpResponse *http.Response) error {
+	if httpResponse.StatusCode >= 200 && httpResponse.StatusCode < 300 {
+		return nil
+	}
+
+	return readAPIError(httpResponse, httpResponse.Body)
+}
+
+// readAPIError builds an *APIError from the error body of httpResponse,
+// read from body. Bodies carrying an info block fill in the status code
+// and messages, any other text is taken as message.
+func readAPIError(httpResponse *http.Response, body io.Reader) *APIError {
+	err := newAPIError(httpResponse)
+	data, _ := io.ReadAll(io.LimitReader(body, maxErrorBodySize))
+
+	var res struct {
+		Info *ResponseInfo `json:"info"`
+	}
+	if json.Unmarshal(data, &res) == nil && res.Info != nil {
+		err.StatusCode = res.Info.StatusCode
+		err.Messages = res.Info.Messages
+	} else if text := strings.TrimSpace(string(data)); text != "" {
+		err.Messages = []string{text}
+	}
+
+	return err
+}
+
+func newAPIError(httpResponse *http.Response) *APIError {
+	err := &APIError{HTTPStatus: httpResponse.StatusCode}
+	if httpResponse.Request != nil && httpResponse.Request.URL != nil {
+		err.Endpoint = httpResponse.Request.URL.Path
+	}
+	for _, h := range requestIDHeaders {
+		if id := httpResponse.Header.Get(h); id != "" {
+			err.RequestID = id
+			break
+		}
+	}
+	return err
+}
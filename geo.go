@@ -0,0 +1,260 @@
+package mapquest
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+const (
+	// EarthRadius is the mean radius of the earth in meters, used by the
+	// spherical computations.
+	EarthRadius = 6371008.8
+
+	// WGS-84 ellipsoid parameters used by VincentyDistance
+	wgs84A = 6378137.0
+	wgs84F = 1 / 298.257223563
+	wgs84B = wgs84A * (1 - wgs84F)
+)
+
+// ErrNoConvergence is returned by VincentyDistance for nearly antipodal
+// points, for which the formula does not converge.
+var ErrNoConvergence = errors.New("vincenty formula failed to converge")
+
+func toRadians(deg float64) float64 { return deg * math.Pi / 180 }
+func toDegrees(rad float64) float64 { return rad * 180 / math.Pi }
+
+// normalizeLongitude maps lng to [-180, 180).
+func normalizeLongitude(lng float64) float64 {
+	lng = math.Mod(lng+180, 360)
+	if lng < 0 {
+		lng += 360
+	}
+	return lng - 180
+}
+
+// HaversineDistance returns the great-circle distance to o in meters,
+// assuming a spherical earth.
+func (s *GeoPoint) HaversineDistance(o GeoPoint) float64 {
+	lat1, lat2 := toRadians(s.Latitude), toRadians(o.Latitude)
+	dLat := lat2 - lat1
+	dLng := toRadians(o.Longitude - s.Longitude)
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * EarthRadius * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+}
+
+// VincentyDistance returns the distance to o in meters on the WGS-84
+// ellipsoid. It is accurate to within millimeters, but fails with
+// ErrNoConvergence for nearly antipodal points.
+func (s *GeoPoint) VincentyDistance(o GeoPoint) (float64, error) {
+	L := toRadians(o.Longitude - s.Longitude)
+	U1 := math.Atan((1 - wgs84F) * math.Tan(toRadians(s.Latitude)))
+	U2 := math.Atan((1 - wgs84F) * math.Tan(toRadians(o.Latitude)))
+	sinU1, cosU1 := math.Sincos(U1)
+	sinU2, cosU2 := math.Sincos(U2)
+
+	lambda := L
+	var sinSigma, cosSigma, sigma, cosSqAlpha, cos2SigmaM float64
+	for i := 0; ; i++ {
+		if i == 200 {
+			return 0, ErrNoConvergence
+		}
+
+		sinLambda, cosLambda := math.Sincos(lambda)
+		sinSigma = math.Sqrt((cosU2*sinLambda)*(cosU2*sinLambda) +
+			(cosU1*sinU2-sinU1*cosU2*cosLambda)*(cosU1*sinU2-sinU1*cosU2*cosLambda))
+		if sinSigma == 0 {
+			return 0, nil // coincident points
+		}
+		cosSigma = sinU1*sinU2 + cosU1*cosU2*cosLambda
+		sigma = math.Atan2(sinSigma, cosSigma)
+		sinAlpha := cosU1 * cosU2 * sinLambda / sinSigma
+		cosSqAlpha = 1 - sinAlpha*sinAlpha
+		cos2SigmaM = 0
+		if cosSqAlpha != 0 {
+			cos2SigmaM = cosSigma - 2*sinU1*sinU2/cosSqAlpha
+		}
+		C := wgs84F / 16 * cosSqAlpha * (4 + wgs84F*(4-3*cosSqAlpha))
+		prev := lambda
+		lambda = L + (1-C)*wgs84F*sinAlpha*(sigma+C*sinSigma*(cos2SigmaM+C*cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)))
+		if math.Abs(lambda-prev) < 1e-12 {
+			break
+		}
+	}
+
+	uSq := cosSqAlpha * (wgs84A*wgs84A - wgs84B*wgs84B) / (wgs84B * wgs84B)
+	A := 1 + uSq/16384*(4096+uSq*(-768+uSq*(320-175*uSq)))
+	B := uSq / 1024 * (256 + uSq*(-128+uSq*(74-47*uSq)))
+	deltaSigma := B * sinSigma * (cos2SigmaM + B/4*(cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)-
+		B/6*cos2SigmaM*(-3+4*sinSigma*sinSigma)*(-3+4*cos2SigmaM*cos2SigmaM)))
+
+	return wgs84B * A * (sigma - deltaSigma), nil
+}
+
+// Bearing returns the initial bearing from s to o in degrees, clockwise
+// from north, in [0, 360).
+func (s *GeoPoint) Bearing(o GeoPoint) float64 {
+	lat1, lat2 := toRadians(s.Latitude), toRadians(o.Latitude)
+	dLng := toRadians(o.Longitude - s.Longitude)
+
+	y := math.Sin(dLng) * math.Cos(lat2)
+	x := math.Cos(lat1)*math.Sin(lat2) - math.Sin(lat1)*math.Cos(lat2)*math.Cos(dLng)
+	return math.Mod(toDegrees(math.Atan2(y, x))+360, 360)
+}
+
+// Destination returns the point reached when travelling distance meters
+// from s along the great circle with the given initial bearing in degrees.
+func (s *GeoPoint) Destination(distance, bearing float64) GeoPoint {
+	lat1, lng1 := toRadians(s.Latitude), toRadians(s.Longitude)
+	d := distance / EarthRadius
+	b := toRadians(bearing)
+
+	lat2 := math.Asin(math.Sin(lat1)*math.Cos(d) + math.Cos(lat1)*math.Sin(d)*math.Cos(b))
+	lng2 := lng1 + math.Atan2(math.Sin(b)*math.Sin(d)*math.Cos(lat1), math.Cos(d)-math.Sin(lat1)*math.Sin(lat2))
+	return GeoPoint{Latitude: toDegrees(lat2), Longitude: normalizeLongitude(toDegrees(lng2))}
+}
+
+// Midpoint returns the point halfway between s and o along the great
+// circle.
+func (s *GeoPoint) Midpoint(o GeoPoint) GeoPoint {
+	lat1, lng1 := toRadians(s.Latitude), toRadians(s.Longitude)
+	lat2 := toRadians(o.Latitude)
+	dLng := toRadians(o.Longitude - s.Longitude)
+
+	bx := math.Cos(lat2) * math.Cos(dLng)
+	by := math.Cos(lat2) * math.Sin(dLng)
+	lat := math.Atan2(math.Sin(lat1)+math.Sin(lat2), math.Sqrt((math.Cos(lat1)+bx)*(math.Cos(lat1)+bx)+by*by))
+	lng := lng1 + math.Atan2(by, math.Cos(lat1)+bx)
+	return GeoPoint{Latitude: toDegrees(lat), Longitude: normalizeLongitude(toDegrees(lng))}
+}
+
+// The longitudes of a BoundingBox span from the west edge (TopLeft) east
+// to the east edge (BottomRight). A box whose west edge lies east of its
+// east edge crosses the antimeridian.
+
+func (s *BoundingBox) north() float64 { return s.TopLeft.Latitude }
+func (s *BoundingBox) south() float64 { return s.BottomRight.Latitude }
+func (s *BoundingBox) west() float64  { return s.TopLeft.Longitude }
+func (s *BoundingBox) east() float64  { return s.BottomRight.Longitude }
+
+// lngSpan returns the width of the box in degrees of longitude.
+func (s *BoundingBox) lngSpan() float64 {
+	if s.west() <= s.east() {
+		return s.east() - s.west()
+	}
+	return s.east() - s.west() + 360
+}
+
+// containsLongitude reports whether lng lies within the longitudes of s.
+func (s *BoundingBox) containsLongitude(lng float64) bool {
+	return math.Mod(lng-s.west()+720, 360) <= s.lngSpan() || s.lngSpan() >= 360
+}
+
+func newBoundingBox(north, west, south, east float64) BoundingBox {
+	if east-west >= 360 {
+		west, east = -180, 180
+	} else {
+		west, east = normalizeLongitude(west), normalizeLongitude(east)
+		if east == -180 {
+			east = 180
+		}
+	}
+	return BoundingBox{
+		TopLeft:     GeoPoint{Latitude: north, Longitude: west},
+		BottomRight: GeoPoint{Latitude: south, Longitude: east},
+	}
+}
+
+// CrossesAntimeridian reports whether the box spans the 180th meridian.
+func (s *BoundingBox) CrossesAntimeridian() bool {
+	return s.west() > s.east()
+}
+
+// Contains reports whether p lies within the box.
+func (s *BoundingBox) Contains(p GeoPoint) bool {
+	return p.Latitude <= s.north() && p.Latitude >= s.south() && s.containsLongitude(p.Longitude)
+}
+
+// Intersects reports whether the box and o overlap.
+func (s *BoundingBox) Intersects(o BoundingBox) bool {
+	if s.south() > o.north() || o.south() > s.north() {
+		return false
+	}
+	return s.containsLongitude(o.west()) || o.containsLongitude(s.west())
+}
+
+// Union returns the smallest box covering both the box and o.
+func (s *BoundingBox) Union(o BoundingBox) BoundingBox {
+	north := math.Max(s.north(), o.north())
+	south := math.Min(s.south(), o.south())
+
+	// span eastwards either from the west edge of s over all of o, or from
+	// the west edge of o over all of s, whichever is narrower; a box
+	// containing the other one spans just itself
+	sToO := math.Max(s.lngSpan(), math.Mod(o.west()-s.west()+720, 360)+o.lngSpan())
+	oToS := math.Max(o.lngSpan(), math.Mod(s.west()-o.west()+720, 360)+s.lngSpan())
+	if sToO <= oToS {
+		return newBoundingBox(north, s.west(), south, s.west()+sToO)
+	}
+	return newBoundingBox(north, o.west(), south, o.west()+oToS)
+}
+
+// Expand returns the box grown by meters on every side. Latitudes are
+// clamped to the poles.
+func (s *BoundingBox) Expand(meters float64) BoundingBox {
+	dLat := toDegrees(meters / EarthRadius)
+	north := math.Min(s.north()+dLat, 90)
+	south := math.Max(s.south()-dLat, -90)
+
+	// a degree of longitude is shortest at the latitude closest to a pole
+	maxLat := math.Max(math.Abs(north), math.Abs(south))
+	if maxLat >= 90 {
+		return newBoundingBox(north, -180, south, 180)
+	}
+	dLng := dLat / math.Cos(toRadians(maxLat))
+	return newBoundingBox(north, s.west()-dLng, south, s.west()+s.lngSpan()+dLng)
+}
+
+// Center returns the center of the box.
+func (s *BoundingBox) Center() GeoPoint {
+	return GeoPoint{
+		Latitude:  (s.north() + s.south()) / 2,
+		Longitude: normalizeLongitude(s.west() + s.lngSpan()/2),
+	}
+}
+
+// BoundingBoxFromPoints returns the smallest box containing all points.
+// Points spread across the antimeridian yield a box crossing it. It
+// returns nil if points is empty.
+func BoundingBoxFromPoints(points []GeoPoint) *BoundingBox {
+	if len(points) == 0 {
+		return nil
+	}
+
+	north, south := points[0].Latitude, points[0].Latitude
+	lngs := make([]float64, len(points))
+	for i, p := range points {
+		north = math.Max(north, p.Latitude)
+		south = math.Min(south, p.Latitude)
+		lngs[i] = normalizeLongitude(p.Longitude)
+	}
+	sort.Float64s(lngs)
+
+	// the box spans everything but the largest gap between neighboring
+	// longitudes, the gap across the antimeridian included
+	west, east := lngs[0], lngs[len(lngs)-1]
+	gap := lngs[0] + 360 - lngs[len(lngs)-1]
+	for i := 1; i < len(lngs); i++ {
+		if d := lngs[i] - lngs[i-1]; d > gap {
+			gap = d
+			west, east = lngs[i], lngs[i-1]
+		}
+	}
+
+	box := BoundingBox{
+		TopLeft:     GeoPoint{Latitude: north, Longitude: west},
+		BottomRight: GeoPoint{Latitude: south, Longitude: east},
+	}
+	return &box
+}
@@ -0,0 +1,241 @@
+package mapquest
+
+import (
+	"math"
+	"testing"
+)
+
+func box(north, west, south, east float64) BoundingBox {
+	return BoundingBox{
+		TopLeft:     GeoPoint{Latitude: north, Longitude: west},
+		BottomRight: GeoPoint{Latitude: south, Longitude: east},
+	}
+}
+
+func TestBoundingBoxUnion(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b BoundingBox
+		want BoundingBox
+	}{
+		{"overlapping", box(10, 0, 0, 10), box(20, 5, 5, 20), box(20, 0, 0, 20)},
+		{"contained", box(10, 0, 0, 10), box(8, 2, 2, 8), box(10, 0, 0, 10)},
+		{"disjoint", box(10, 0, 0, 10), box(10, 30, 0, 40), box(10, 0, 0, 40)},
+		{"disjoint across antimeridian", box(10, 170, 0, 175), box(10, -175, 0, -170), box(10, 170, 0, -170)},
+		{"crossing antimeridian", box(10, 170, 0, -170), box(10, -175, 0, -160), box(10, 170, 0, -160)},
+		{"overlapping across antimeridian", box(10, 160, 0, -175), box(10, 175, 0, -160), box(10, 160, 0, -160)},
+		{"covering the globe", box(10, 0, 0, 200-360), box(10, 180, 0, 20), box(10, -180, 0, 180)},
+	}
+
+	for _, tt := range tests {
+		for _, order := range [][2]BoundingBox{{tt.a, tt.b}, {tt.b, tt.a}} {
+			if got := order[0].Union(order[1]); got != tt.want {
+				t.Errorf("%s: %v.Union(%v) = %v, want %v", tt.name, order[0], order[1], got, tt.want)
+			}
+		}
+	}
+}
+
+// reference values from the examples of
+// https://www.movable-type.co.uk/scripts/latlong.html, rounded to the
+// arc second, and from Vincenty's paper
+
+var (
+	landsEnd     = GeoPoint{Latitude: 50.0663889, Longitude: -5.7147222}
+	johnOGroats  = GeoPoint{Latitude: 58.6438889, Longitude: -3.07}
+	flindersPeak = GeoPoint{Latitude: -37.95103342, Longitude: 144.42486789}
+	buninyong    = GeoPoint{Latitude: -37.65282114, Longitude: 143.92649554}
+)
+
+func nearPoint(a, b GeoPoint, tolerance float64) bool {
+	return math.Abs(a.Latitude-b.Latitude) <= tolerance && math.Abs(a.Longitude-b.Longitude) <= tolerance
+}
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		name      string
+		a, b      GeoPoint
+		haversine float64
+		vincenty  float64
+		tolerance float64
+	}{
+		// the ellipsoid makes this route a kilometer longer
+		{"Land's End to John o' Groats", landsEnd, johnOGroats, 968900, 969900, 100},
+		{"Flinders Peak to Buninyong", flindersPeak, buninyong, 54900, 54972.271, 0.001},
+		{"same point", landsEnd, landsEnd, 0, 0, 0},
+	}
+
+	for _, tt := range tests {
+		if got := tt.a.HaversineDistance(tt.b); math.Abs(got-tt.haversine) > 100 {
+			t.Errorf("%s: haversine distance %.0f m, want %.0f m", tt.name, got, tt.haversine)
+		}
+		got, err := tt.a.VincentyDistance(tt.b)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+		} else if math.Abs(got-tt.vincenty) > tt.tolerance {
+			t.Errorf("%s: vincenty distance %.3f m, want %.3f m", tt.name, got, tt.vincenty)
+		}
+	}
+
+	// nearly antipodal points
+	a, b := GeoPoint{Latitude: 0, Longitude: 0}, GeoPoint{Latitude: 0.5, Longitude: 179.7}
+	if _, err := a.VincentyDistance(b); err != ErrNoConvergence {
+		t.Errorf("antipodal: got %v, want ErrNoConvergence", err)
+	}
+}
+
+func TestBearing(t *testing.T) {
+	tests := []struct {
+		a, b GeoPoint
+		want float64
+	}{
+		{landsEnd, johnOGroats, 9.1198},
+		{johnOGroats, landsEnd, 191.2752},
+		{GeoPoint{Latitude: 0, Longitude: 0}, GeoPoint{Latitude: 0, Longitude: -10}, 270},
+		{GeoPoint{Latitude: 0, Longitude: 179}, GeoPoint{Latitude: 0, Longitude: -179}, 90},
+	}
+	for _, tt := range tests {
+		if got := tt.a.Bearing(tt.b); math.Abs(got-tt.want) > 1e-3 {
+			t.Errorf("%v.Bearing(%v) = %.4f, want %.4f", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestDestination(t *testing.T) {
+	tests := []struct {
+		start    GeoPoint
+		distance float64
+		bearing  float64
+		want     GeoPoint
+	}{
+		{GeoPoint{Latitude: 53.3205556, Longitude: -1.7297222}, 124800, 96.0216667, GeoPoint{Latitude: 53.1882691, Longitude: 0.1332744}},
+		{GeoPoint{Latitude: 0, Longitude: 179.5}, 111195, 90, GeoPoint{Latitude: 0, Longitude: -179.5}},
+		{landsEnd, 0, 45, landsEnd},
+	}
+	for _, tt := range tests {
+		if got := tt.start.Destination(tt.distance, tt.bearing); !nearPoint(got, tt.want, 1e-4) {
+			t.Errorf("%v.Destination(%v, %v) = %v, want %v", tt.start, tt.distance, tt.bearing, got, tt.want)
+		}
+	}
+}
+
+func TestMidpoint(t *testing.T) {
+	tests := []struct {
+		a, b GeoPoint
+		want GeoPoint
+	}{
+		{landsEnd, johnOGroats, GeoPoint{Latitude: 54.3622222, Longitude: -4.5305556}},
+		{GeoPoint{Latitude: 0, Longitude: 170}, GeoPoint{Latitude: 0, Longitude: -170}, GeoPoint{Latitude: 0, Longitude: -180}},
+	}
+	for _, tt := range tests {
+		if got := tt.a.Midpoint(tt.b); !nearPoint(got, tt.want, 3e-4) {
+			t.Errorf("%v.Midpoint(%v) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestBoundingBoxContains(t *testing.T) {
+	tests := []struct {
+		box  BoundingBox
+		p    GeoPoint
+		want bool
+	}{
+		{box(10, 0, 0, 10), GeoPoint{Latitude: 5, Longitude: 5}, true},
+		{box(10, 0, 0, 10), GeoPoint{Latitude: 10, Longitude: 0}, true},
+		{box(10, 0, 0, 10), GeoPoint{Latitude: 11, Longitude: 5}, false},
+		{box(10, 0, 0, 10), GeoPoint{Latitude: 5, Longitude: -5}, false},
+		{box(10, 170, 0, -170), GeoPoint{Latitude: 5, Longitude: 180}, true},
+		{box(10, 170, 0, -170), GeoPoint{Latitude: 5, Longitude: -175}, true},
+		{box(10, 170, 0, -170), GeoPoint{Latitude: 5, Longitude: 0}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.box.Contains(tt.p); got != tt.want {
+			t.Errorf("%v.Contains(%v) = %v, want %v", tt.box, tt.p, got, tt.want)
+		}
+	}
+}
+
+func TestBoundingBoxIntersects(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b BoundingBox
+		want bool
+	}{
+		{"overlapping", box(10, 0, 0, 10), box(20, 5, 5, 20), true},
+		{"contained", box(10, 0, 0, 10), box(8, 2, 2, 8), true},
+		{"apart in latitude", box(10, 0, 0, 10), box(30, 0, 20, 10), false},
+		{"apart in longitude", box(10, 0, 0, 10), box(10, 20, 0, 30), false},
+		{"across antimeridian", box(10, 170, 0, -170), box(10, -175, 0, -160), true},
+		{"beside antimeridian", box(10, 170, 0, -170), box(10, -160, 0, -150), false},
+	}
+	for _, tt := range tests {
+		for _, order := range [][2]BoundingBox{{tt.a, tt.b}, {tt.b, tt.a}} {
+			if got := order[0].Intersects(order[1]); got != tt.want {
+				t.Errorf("%s: %v.Intersects(%v) = %v, want %v", tt.name, order[0], order[1], got, tt.want)
+			}
+		}
+	}
+}
+
+func TestBoundingBoxExpand(t *testing.T) {
+	// a degree of latitude is 111195 m
+	tests := []struct {
+		name   string
+		box    BoundingBox
+		meters float64
+		want   BoundingBox
+	}{
+		{"equator", box(1, -1, -1, 1), 111195, box(2, -2.0006, -2, 2.0006)},
+		{"antimeridian", box(1, 179, -1, -179), 111195, box(2, 177.9994, -2, -177.9994)},
+		{"pole", box(89.5, 0, 80, 10), 111195, box(90, -180, 79, 180)},
+	}
+	for _, tt := range tests {
+		got := tt.box.Expand(tt.meters)
+		if !nearPoint(got.TopLeft, tt.want.TopLeft, 1e-3) || !nearPoint(got.BottomRight, tt.want.BottomRight, 1e-3) {
+			t.Errorf("%s: %v.Expand(%v) = %v, want %v", tt.name, tt.box, tt.meters, got, tt.want)
+		}
+	}
+}
+
+func TestBoundingBoxCenter(t *testing.T) {
+	tests := []struct {
+		box  BoundingBox
+		want GeoPoint
+	}{
+		{box(10, 0, 0, 10), GeoPoint{Latitude: 5, Longitude: 5}},
+		{box(10, 170, -10, -160), GeoPoint{Latitude: 0, Longitude: -175}},
+		{box(10, 175, 0, -175), GeoPoint{Latitude: 5, Longitude: -180}},
+	}
+	for _, tt := range tests {
+		if got := tt.box.Center(); !nearPoint(got, tt.want, 1e-9) {
+			t.Errorf("%v.Center() = %v, want %v", tt.box, got, tt.want)
+		}
+	}
+}
+
+func TestBoundingBoxFromPoints(t *testing.T) {
+	tests := []struct {
+		name   string
+		points []GeoPoint
+		want   BoundingBox
+	}{
+		{"single point", []GeoPoint{{Latitude: 5, Longitude: 5}}, box(5, 5, 5, 5)},
+		{"Great Britain", []GeoPoint{landsEnd, johnOGroats}, box(58.6438889, -5.7147222, 50.0663889, -3.07)},
+		{"Fiji across antimeridian", []GeoPoint{{Latitude: -16, Longitude: 179}, {Latitude: -18, Longitude: -179}, {Latitude: -17, Longitude: 178}}, box(-16, 178, -18, -179)},
+		{"normalized longitudes", []GeoPoint{{Latitude: 0, Longitude: 190}, {Latitude: 1, Longitude: -175}}, box(1, -175, 0, -170)},
+		{"around the globe", []GeoPoint{{Latitude: 0, Longitude: -120}, {Latitude: 0, Longitude: 0}, {Latitude: 0, Longitude: 120}}, box(0, -120, 0, 120)},
+	}
+	for _, tt := range tests {
+		got := BoundingBoxFromPoints(tt.points)
+		if got == nil || !nearPoint(got.TopLeft, tt.want.TopLeft, 1e-9) || !nearPoint(got.BottomRight, tt.want.BottomRight, 1e-9) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+			continue
+		}
+		if got.CrossesAntimeridian() != tt.want.CrossesAntimeridian() {
+			t.Errorf("%s: %v crosses the antimeridian: %v", tt.name, got, got.CrossesAntimeridian())
+		}
+	}
+	if got := BoundingBoxFromPoints(nil); got != nil {
+		t.Errorf("no points: got %v, want nil", got)
+	}
+}
@@ -1,8 +1,10 @@
 package mapquest
 
 import (
+	"context"
 	"encoding/json"
-	"net/http"
+	"fmt"
+	"net/url"
 
 	"github.com/google/go-querystring/query"
 )
@@ -10,11 +12,14 @@ import (
 const (
 	GeocodingPrefix  = "geocoding"
 	GeocodingVersion = "v1"
+
+	// GeocodeBatchLimit is the maximum number of locations the batch
+	// endpoint accepts per call.
+	GeocodeBatchLimit = 100
 )
 
 // GeocodingAPI enables users to request geocoding searches via the
 // MapQuest API. See https://developer.mapquest.com/documentation/open/geocoding-api/ for details.
-// Batch API is not implemented, 5 point queries are not supported
 type GeocodingAPI struct {
 	c *Client
 }
@@ -26,7 +31,20 @@ func (api *GeocodingAPI) SimpleAddress(location string, limit int) (*GeocodeAddr
 	return api.Address(&GeocodeAddressRequest{Location: location, Limit: limit})
 }
 
+// Address geocodes the location given in req.
 func (api *GeocodingAPI) Address(req *GeocodeAddressRequest) (*GeocodeAddressResponse, error) {
+	return api.AddressContext(context.Background(), req)
+}
+
+// AddressContext is like Address, but binds the request to ctx.
+func (api *GeocodingAPI) AddressContext(ctx context.Context, req *GeocodeAddressRequest) (*GeocodeAddressResponse, error) {
+	if err := validateLocation(req.Location, req.Address); err != nil {
+		return nil, err
+	}
+	if err := req.IntlMode.validate(req.Location, req.Address); err != nil {
+		return nil, err
+	}
+
 	q, err := query.Values(req)
 	if err != nil {
 		return nil, err
@@ -34,23 +52,15 @@ func (api *GeocodingAPI) Address(req *GeocodeAddressRequest) (*GeocodeAddressRes
 
 	q.Set("key", api.c.key)
 	q.Set("outFormat", "json")
-	u := apiURL(GeocodingPrefix, GeocodingVersion, "address")
+	u := api.c.apiURL(ServiceGeocoding, GeocodingVersion, "address")
 	u.RawQuery = q.Encode()
 
-	httpRequest, err := http.NewRequest("GET", u.String(), nil)
-	if err != nil {
+	res := new(GeocodeAddressResponse)
+	if err := api.c.getCachedJSON(ctx, ServiceGeocoding, u, res); err != nil {
 		return nil, err
 	}
-	httpRequest.Header.Set("User-Agent", UserAgent)
-	httpResponse, err := api.c.httpClient.Do(httpRequest)
-	if err != nil {
-		return nil, err
-	}
-	defer httpResponse.Body.Close()
-
-	var res *GeocodeAddressResponse
-	if err := json.NewDecoder(httpResponse.Body).Decode(res); err != nil {
-		return nil, err
+	for _, entry := range res.Results {
+		entry.echoAddress(req.Address)
 	}
 
 	return res, nil
@@ -60,7 +70,13 @@ func (api *GeocodingAPI) SimpleReverse(lat, long float64) (*GeocodeAddressRespon
 	return api.Reverse(&GeocodeReverseRequest{Location: &GeoPoint{Latitude: lat, Longitude: long}})
 }
 
+// Reverse looks up the address of the location given in req.
 func (api *GeocodingAPI) Reverse(req *GeocodeReverseRequest) (*GeocodeAddressResponse, error) {
+	return api.ReverseContext(context.Background(), req)
+}
+
+// ReverseContext is like Reverse, but binds the request to ctx.
+func (api *GeocodingAPI) ReverseContext(ctx context.Context, req *GeocodeReverseRequest) (*GeocodeAddressResponse, error) {
 	q, err := query.Values(req)
 	if err != nil {
 		return nil, err
@@ -68,65 +84,171 @@ func (api *GeocodingAPI) Reverse(req *GeocodeReverseRequest) (*GeocodeAddressRes
 
 	q.Set("key", api.c.key)
 	q.Set("outFormat", "json")
-	u := apiURL(GeocodingPrefix, GeocodingVersion, "reverse")
+	u := api.c.apiURL(ServiceGeocoding, GeocodingVersion, "reverse")
 	u.RawQuery = q.Encode()
 
-	httpRequest, err := http.NewRequest("GET", u.String(), nil)
-	if err != nil {
+	res := new(GeocodeAddressResponse)
+	if err := api.c.getCachedJSON(ctx, ServiceGeocoding, u, res); err != nil {
 		return nil, err
 	}
-	httpRequest.Header.Set("User-Agent", UserAgent)
-	httpResponse, err := api.c.httpClient.Do(httpRequest)
-	if err != nil {
-		return nil, err
+
+	return res, nil
+}
+
+// Batch geocodes all locations given in req. Requests with more than
+// GeocodeBatchLimit locations are split into several calls. The results
+// are correlated to the locations of req by their index.
+func (api *GeocodingAPI) Batch(ctx context.Context, req *GeocodeBatchRequest) (*GeocodeBatchResponse, error) {
+	q := make(url.Values)
+	q.Set("key", api.c.key)
+	q.Set("outFormat", "json")
+	u := api.c.apiURL(ServiceGeocoding, GeocodingVersion, "batch")
+	u.RawQuery = q.Encode()
+
+	for _, l := range req.Locations {
+		if err := l.validate(); err != nil {
+			return nil, err
+		}
+		// coordinates are not parsed, whatever the intlMode
+		if l.LatLong != nil {
+			continue
+		}
+		if err := req.IntlMode.validate(l.Text, l.Address); err != nil {
+			return nil, err
+		}
 	}
-	defer httpResponse.Body.Close()
 
-	var res *GeocodeAddressResponse
-	if err := json.NewDecoder(httpResponse.Body).Decode(res); err != nil {
-		return nil, err
+	res := &GeocodeBatchResponse{Results: make([]*GeocodeBatchResult, 0, len(req.Locations))}
+	for start := 0; start < len(req.Locations); start += GeocodeBatchLimit {
+		end := start + GeocodeBatchLimit
+		if end > len(req.Locations) {
+			end = len(req.Locations)
+		}
+
+		body := &geocodeBatchBody{Locations: req.Locations[start:end]}
+		body.Options.BoundingBox = req.BoundingBox
+		body.Options.IgnoreLatLongInput = req.IgnoreLatLongInput
+		body.Options.ThumbMaps = req.ThumbMaps
+		body.Options.Limit = req.Limit
+		body.Options.IntlMode = req.IntlMode
+
+		chunk := new(GeocodeAddressResponse)
+		if err := api.c.postJSON(ctx, ServiceGeocoding, u, body, chunk); err != nil {
+			return nil, err
+		}
+
+		if res.Info == nil {
+			res.Info = chunk.Info
+		}
+		for i, entry := range chunk.Results {
+			if start+i < end {
+				entry.echoAddress(req.Locations[start+i].Address)
+			}
+			res.Results = append(res.Results, &GeocodeBatchResult{Index: start + i, Entry: entry})
+		}
 	}
 
 	return res, nil
 }
 
+// GeocodeAddressRequest is a forward geocoding request. Set either
+// Location to a single line address, or Address to a structured address.
 type GeocodeAddressRequest struct {
-	Location           string       `url:"location"`
-	BoundingBox        *BoundingBox `url:"boundingBox,omitempty"`
-	IgnoreLatLongInput bool         `url:"ignoreLatLngInput,omitempty"`
-	ThumbMaps          bool         `url:"thumbMaps"` // dont omit, omitempty works on false, default is true though
-	Limit              int          `url:"maxResults,omitempty"`
+	Location           string          `url:"location,omitempty"`
+	Address            *GeocodeAddress `url:"address,omitempty"`
+	BoundingBox        *BoundingBox    `url:"boundingBox,omitempty"`
+	IgnoreLatLongInput bool            `url:"ignoreLatLngInput,omitempty"`
+	ThumbMaps          bool            `url:"thumbMaps"` // dont omit, omitempty works on false, default is true though
+	Limit              int             `url:"maxResults,omitempty"`
+	IntlMode           IntlMode        `url:"intlMode,omitempty"`
 
 	// fields ignored:
 	// - delimiter => csv output only
-	// - intlMode => feel free to implement
+}
+
+// validateLocation rejects a location given both as single line and as
+// structured address.
+func validateLocation(location string, addr *GeocodeAddress) error {
+	if location != "" && addr != nil {
+		return ErrLocationConflict
+	}
+	return nil
+}
+
+// IntlMode selects how international addresses are parsed. It does not
+// change the fields of the response, which only echoes it in its options.
+// See https://developer.mapquest.com/documentation/geocoding-api/address/get/
+type IntlMode string
+
+const (
+	// IntlModeAuto lets the API decide how to parse the location.
+	IntlModeAuto IntlMode = "AUTO"
+	// IntlMode5Box requires a structured address.
+	IntlMode5Box IntlMode = "5BOX"
+	// IntlMode1Box requires a single line address, which is parsed with
+	// the conventions of its country.
+	IntlMode1Box IntlMode = "1BOX"
+)
+
+// validate rejects a location the API would not accept in intlMode m.
+func (m IntlMode) validate(location string, addr *GeocodeAddress) error {
+	switch m {
+	case "", IntlModeAuto:
+	case IntlMode5Box:
+		if addr == nil {
+			return fmt.Errorf("%w: %s requires a structured address", ErrInvalidIntlMode, m)
+		}
+	case IntlMode1Box:
+		if addr != nil {
+			return fmt.Errorf("%w: %s requires a single line address", ErrInvalidIntlMode, m)
+		}
+	default:
+		return fmt.Errorf("%w: %q", ErrInvalidIntlMode, string(m))
+	}
+	return nil
 }
 
 type GeocodeAddressResponse struct {
-	Info *struct {
-		StatusCode int `json:"statuscode,omitempty"` // https://developer.mapquest.com/documentation/geocoding-api/status-codes
-		Copyright  *struct {
-			Text         string `json:"text,omitempty"`
-			ImageURL     string `json:"imageUrl,omitempty"`
-			ImageAltText string `json:"imageAltText,omitempty"`
-		} `json:"copyright,omitempty"`
-		Messages []string `json:"messages,omitempty"`
-	} `json:"info,omitempty"`
+	Info    *ResponseInfo `json:"info,omitempty"`
 	Options *struct {
-		MaxResults         int  `json:"maxResults,omitempty"`
-		ThumbMaps          bool `json:"thumbMaps"` // dont omit, omitempty works on false, default is true though
-		IgnoreLatLongInput bool `json:"ignoreLatLngInput,omitempty"`
+		MaxResults         int      `json:"maxResults,omitempty"`
+		ThumbMaps          bool     `json:"thumbMaps"` // dont omit, omitempty works on false, default is true though
+		IgnoreLatLongInput bool     `json:"ignoreLatLngInput,omitempty"`
+		IntlMode           IntlMode `json:"intlMode,omitempty"`
 	} `json:"options,omitempty"`
 
 	Results []*GeocodeAddressResponseEntry `json:"results,omitempty"`
 }
 
+func (res *GeocodeAddressResponse) responseInfo() *ResponseInfo {
+	return res.Info
+}
+
 type GeocodeAddressResponseEntry struct {
-	ProvidedLocation *struct {
-		Location string    `json:"location,omitempty"`
-		LatLong  *GeoPoint `json:"latLng,omitempty"`
-	} `json:"providedLocation,omitempty"` // this needs probably 5point support
-	Locations []*GeocodeAddressResponseLocationEntry `json:"location,omitempty"`
+	ProvidedLocation *GeocodeProvidedLocation               `json:"providedLocation,omitempty"`
+	Locations        []*GeocodeAddressResponseLocationEntry `json:"locations,omitempty"`
+}
+
+// GeocodeProvidedLocation echoes the location of a request. Structured
+// addresses are echoed in the embedded GeocodeAddress.
+type GeocodeProvidedLocation struct {
+	Location string    `json:"location,omitempty"`
+	LatLong  *GeoPoint `json:"latLng,omitempty"`
+	GeocodeAddress
+}
+
+// echoAddress fills in the structured address of the provided location,
+// if the response did not echo it already.
+func (s *GeocodeAddressResponseEntry) echoAddress(addr *GeocodeAddress) {
+	if addr == nil {
+		return
+	}
+	if s.ProvidedLocation == nil {
+		s.ProvidedLocation = new(GeocodeProvidedLocation)
+	}
+	if s.ProvidedLocation.GeocodeAddress == (GeocodeAddress{}) {
+		s.ProvidedLocation.GeocodeAddress = *addr
+	}
 }
 
 type GeocodeType string
@@ -181,6 +303,40 @@ type GeocodeAddressResponseLocationEntry struct {
 	// - sideOfStreet => Specifies the side of street. (Left, Right, Mixed, None)
 }
 
+type GeocodeBatchRequest struct {
+	Locations          []Location
+	BoundingBox        *BoundingBox
+	IgnoreLatLongInput bool
+	ThumbMaps          bool
+	Limit              int
+	IntlMode           IntlMode
+}
+
+// geocodeBatchBody is the JSON body posted to the batch endpoint.
+type geocodeBatchBody struct {
+	Locations []Location `json:"locations"`
+	Options   struct {
+		BoundingBox        *BoundingBox `json:"boundingBox,omitempty"`
+		IgnoreLatLongInput bool         `json:"ignoreLatLngInput,omitempty"`
+		ThumbMaps          bool         `json:"thumbMaps"` // dont omit, omitempty works on false, default is true though
+		Limit              int          `json:"maxResults,omitempty"`
+		IntlMode           IntlMode     `json:"intlMode,omitempty"`
+	} `json:"options"`
+}
+
+type GeocodeBatchResponse struct {
+	// Info is the info block of the first call.
+	Info    *ResponseInfo
+	Results []*GeocodeBatchResult
+}
+
+// GeocodeBatchResult is the result for the location at Index of the
+// GeocodeBatchRequest.
+type GeocodeBatchResult struct {
+	Index int
+	Entry *GeocodeAddressResponseEntry
+}
+
 type GeocodeReverseRequest struct {
 	Location                   *GeoPoint `url:"location"`
 	ThumbMaps                  bool      `url:"thumbMaps"` // dont omit, omitempty works on false, default is true though
@@ -0,0 +1,179 @@
+package mapquest_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/cking/mapquest"
+	"github.com/cking/mapquest/mapquesttest"
+)
+
+func TestGeocodeStructuredAddress(t *testing.T) {
+	srv := mapquesttest.NewServer()
+	defer srv.Close()
+	client := srv.Client("key")
+
+	addr := &mapquest.GeocodeAddress{Street: "1090 N Charlotte St", City: "Lancaster", State: "PA", PostalCode: "17603"}
+	res, err := client.Geocoding().AddressContext(context.Background(), &mapquest.GeocodeAddressRequest{Address: addr})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	q := srv.RequestsTo(mapquesttest.PathGeocodeAddress)[0].Query
+	if q.Get("street") != addr.Street || q.Get("city") != addr.City || q.Get("postalCode") != addr.PostalCode || q.Has("location") {
+		t.Errorf("got query %s", q.Encode())
+	}
+	if got := res.Results[0].ProvidedLocation.GeocodeAddress; got != *addr {
+		t.Errorf("got provided address %+v, want %+v", got, *addr)
+	}
+	if len(res.Results[0].Locations) != 1 || *res.Results[0].Locations[0].LatLong != mapquesttest.DefaultPoint {
+		t.Errorf("got locations %+v", res.Results[0].Locations)
+	}
+}
+
+func TestGeocodeLocationConflict(t *testing.T) {
+	srv := mapquesttest.NewServer()
+	defer srv.Close()
+	client := srv.Client("key")
+	ctx := context.Background()
+	addr := &mapquest.GeocodeAddress{City: "Lancaster", State: "PA"}
+
+	_, err := client.Geocoding().AddressContext(ctx, &mapquest.GeocodeAddressRequest{Location: "Lancaster, PA", Address: addr})
+	if !errors.Is(err, mapquest.ErrLocationConflict) {
+		t.Errorf("address: got %v, want ErrLocationConflict", err)
+	}
+	point := &mapquesttest.DefaultPoint
+	for _, l := range []mapquest.Location{
+		{Text: "Lancaster, PA", Address: addr},
+		{Text: "Lancaster, PA", LatLong: point},
+		{LatLong: point, Address: addr},
+		{Text: "Lancaster, PA", LatLong: point, Address: addr},
+	} {
+		_, err = client.Geocoding().Batch(ctx, &mapquest.GeocodeBatchRequest{Locations: []mapquest.Location{{Text: "York, PA"}, l}})
+		if !errors.Is(err, mapquest.ErrLocationConflict) {
+			t.Errorf("batch %+v: got %v, want ErrLocationConflict", l, err)
+		}
+	}
+	if n := len(srv.Requests()); n != 0 {
+		t.Errorf("got %d requests, want none", n)
+	}
+}
+
+func TestGeocodeIntlMode(t *testing.T) {
+	srv := mapquesttest.NewServer()
+	defer srv.Close()
+	client := srv.Client("key")
+	ctx := context.Background()
+	addr := &mapquest.GeocodeAddress{Street: "Unter den Linden 77", City: "Berlin", Country: "DE"}
+
+	for _, tt := range []struct {
+		req     *mapquest.GeocodeAddressRequest
+		wantErr bool
+	}{
+		{&mapquest.GeocodeAddressRequest{Location: "Berlin", IntlMode: mapquest.IntlModeAuto}, false},
+		{&mapquest.GeocodeAddressRequest{Address: addr, IntlMode: mapquest.IntlModeAuto}, false},
+		{&mapquest.GeocodeAddressRequest{Address: addr, IntlMode: mapquest.IntlMode5Box}, false},
+		{&mapquest.GeocodeAddressRequest{Location: "Berlin", IntlMode: mapquest.IntlMode5Box}, true},
+		{&mapquest.GeocodeAddressRequest{Location: "Berlin", IntlMode: mapquest.IntlMode1Box}, false},
+		{&mapquest.GeocodeAddressRequest{Address: addr, IntlMode: mapquest.IntlMode1Box}, true},
+		{&mapquest.GeocodeAddressRequest{Location: "Berlin", IntlMode: "3BOX"}, true},
+	} {
+		srv.Reset()
+		_, err := client.Geocoding().AddressContext(ctx, tt.req)
+		if tt.wantErr {
+			if !errors.Is(err, mapquest.ErrInvalidIntlMode) || len(srv.Requests()) != 0 {
+				t.Errorf("%s: got %v after %d requests, want ErrInvalidIntlMode", tt.req.IntlMode, err, len(srv.Requests()))
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tt.req.IntlMode, err)
+			continue
+		}
+		if got := srv.Requests()[0].Query.Get("intlMode"); got != string(tt.req.IntlMode) {
+			t.Errorf("got intlMode %q, want %q", got, tt.req.IntlMode)
+		}
+	}
+
+	_, err := client.Geocoding().Batch(ctx, &mapquest.GeocodeBatchRequest{
+		Locations: []mapquest.Location{{Address: addr}, {Text: "Berlin"}},
+		IntlMode:  mapquest.IntlMode5Box,
+	})
+	if !errors.Is(err, mapquest.ErrInvalidIntlMode) {
+		t.Errorf("batch: got %v, want ErrInvalidIntlMode", err)
+	}
+
+	// coordinates are accepted in any intlMode
+	srv.Reset()
+	_, err = client.Geocoding().Batch(ctx, &mapquest.GeocodeBatchRequest{
+		Locations: []mapquest.Location{{Address: addr}, {LatLong: &mapquesttest.DefaultPoint}},
+		IntlMode:  mapquest.IntlMode5Box,
+	})
+	if err != nil {
+		t.Errorf("batch with coordinates: %v", err)
+	}
+	if n := len(srv.Requests()); n != 1 {
+		t.Errorf("batch with coordinates: got %d requests, want 1", n)
+	}
+}
+
+func TestGeocodeBatchChunks(t *testing.T) {
+	srv := mapquesttest.NewServer()
+	defer srv.Close()
+	client := srv.Client("key")
+
+	locations := make([]mapquest.Location, 2*mapquest.GeocodeBatchLimit+5)
+	for i := range locations {
+		locations[i] = mapquest.Location{Text: fmt.Sprintf("%d Main St, Lancaster, PA", i)}
+	}
+	res, err := client.Geocoding().Batch(context.Background(), &mapquest.GeocodeBatchRequest{Locations: locations})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	requests := srv.RequestsTo(mapquesttest.PathGeocodeBatch)
+	if len(requests) != 3 {
+		t.Fatalf("got %d requests, want 3", len(requests))
+	}
+	for i, want := range []int{100, 100, 5} {
+		var body struct {
+			Locations []json.RawMessage `json:"locations"`
+		}
+		if err := json.Unmarshal(requests[i].Body, &body); err != nil {
+			t.Fatal(err)
+		}
+		if requests[i].Method != "POST" || len(body.Locations) != want {
+			t.Errorf("request %d: got %s with %d locations, want POST with %d", i, requests[i].Method, len(body.Locations), want)
+		}
+	}
+
+	if len(res.Results) != len(locations) {
+		t.Fatalf("got %d results, want %d", len(res.Results), len(locations))
+	}
+	for i, r := range res.Results {
+		if r.Index != i || r.Entry.ProvidedLocation.Location != locations[i].Text {
+			t.Errorf("result %d: got index %d for %q", i, r.Index, r.Entry.ProvidedLocation.Location)
+		}
+	}
+}
+
+func TestGeocodeResponseLocations(t *testing.T) {
+	data := `{"info":{"statuscode":0},"results":[{"providedLocation":{"location":"Lancaster, PA"},"locations":[
+		{"latLng":{"lat":40.037875,"lng":-76.305514},"adminArea5":"Lancaster","geocodeQualityCode":"A5XAX"},
+		{"latLng":{"lat":34.686785,"lng":-118.154163},"adminArea5":"Lancaster","geocodeQualityCode":"A5XAX"}]}]}`
+
+	var res mapquest.GeocodeAddressResponse
+	if err := json.Unmarshal([]byte(data), &res); err != nil {
+		t.Fatal(err)
+	}
+	locations := res.Results[0].Locations
+	if len(locations) != 2 {
+		t.Fatalf("decoded %d locations, want 2", len(locations))
+	}
+	if got, want := *locations[1].LatLong, (mapquest.GeoPoint{Latitude: 34.686785, Longitude: -118.154163}); got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,190 @@
+package mapquest
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// GeoJSONGeometry is a GeoJSON geometry as described in RFC 7946.
+// Coordinates are ordered longitude first.
+type GeoJSONGeometry struct {
+	Type        string      `json:"type"`
+	Coordinates interface{} `json:"coordinates"`
+}
+
+// GeoJSONFeature is a GeoJSON feature.
+type GeoJSONFeature struct {
+	Type       string                 `json:"type"`
+	Geometry   *GeoJSONGeometry       `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+	BBox       []float64              `json:"bbox,omitempty"`
+}
+
+// GeoJSONFeatureCollection is a GeoJSON feature collection.
+type GeoJSONFeatureCollection struct {
+	Type     string            `json:"type"`
+	Features []*GeoJSONFeature `json:"features"`
+	BBox     []float64         `json:"bbox,omitempty"`
+}
+
+// NewGeoJSONFeatureCollection creates a collection of features. Its bbox
+// covers the points of all Point features. Features without a geometry
+// are allowed, but do not count towards the bbox.
+func NewGeoJSONFeatureCollection(features []*GeoJSONFeature) *GeoJSONFeatureCollection {
+	fc := &GeoJSONFeatureCollection{Type: "FeatureCollection", Features: features}
+	if fc.Features == nil {
+		fc.Features = []*GeoJSONFeature{}
+	}
+
+	var points []GeoPoint
+	for _, f := range features {
+		if f == nil || f.Geometry == nil || f.Geometry.Type != "Point" {
+			continue
+		}
+		if c, ok := f.Geometry.Coordinates.([]float64); ok && len(c) >= 2 {
+			points = append(points, GeoPoint{Latitude: c[1], Longitude: c[0]})
+		}
+	}
+	if box := BoundingBoxFromPoints(points); box != nil {
+		fc.BBox = box.geoJSONBBox()
+	}
+	return fc
+}
+
+// GeoJSONPoint returns a Point geometry at p.
+func GeoJSONPoint(p GeoPoint) *GeoJSONGeometry {
+	return &GeoJSONGeometry{Type: "Point", Coordinates: []float64{p.Longitude, p.Latitude}}
+}
+
+// GeoJSONLineString returns a LineString geometry along points, e.g. a
+// track or a decoded route shape.
+func GeoJSONLineString(points []GeoPoint) *GeoJSONGeometry {
+	coords := make([][]float64, len(points))
+	for i, p := range points {
+		coords[i] = []float64{p.Longitude, p.Latitude}
+	}
+	return &GeoJSONGeometry{Type: "LineString", Coordinates: coords}
+}
+
+// geoJSONBBox returns the box as GeoJSON bbox, i.e. west, south, east,
+// north. Boxes crossing the antimeridian keep a west edge greater than
+// their east edge, as RFC 7946 demands.
+func (s *BoundingBox) geoJSONBBox() []float64 {
+	return []float64{s.west(), s.south(), s.east(), s.north()}
+}
+
+// setNonEmpty adds the non-empty values to props.
+func setNonEmpty(props map[string]interface{}, kv ...string) {
+	for i := 0; i+1 < len(kv); i += 2 {
+		if kv[i+1] != "" {
+			props[kv[i]] = kv[i+1]
+		}
+	}
+}
+
+// ToFeatureCollection returns one Point feature per location of all
+// results. The properties carry the normalized address along with the
+// geocode quality, and the index of the result and location.
+func (res *GeocodeAddressResponse) ToFeatureCollection() *GeoJSONFeatureCollection {
+	var features []*GeoJSONFeature
+	for i, result := range res.Results {
+		for j, l := range result.Locations {
+			if l.LatLong == nil {
+				continue
+			}
+
+			props := map[string]interface{}{
+				"resultIndex":   i,
+				"locationIndex": j,
+			}
+			if result.ProvidedLocation != nil {
+				setNonEmpty(props, "providedLocation", result.ProvidedLocation.Location)
+			}
+			setNonEmpty(props,
+				"street", l.Street,
+				"neighborhood", l.AdminArea6,
+				"city", l.AdminArea5,
+				"county", l.AdminArea4,
+				"state", l.AdminArea3,
+				"postalCode", l.PostalCode,
+				"countryCode", l.AdminArea1,
+				"quality", l.GeocodeQuality,
+				"qualityCode", l.GeocodeQualityCode,
+			)
+
+			features = append(features, &GeoJSONFeature{
+				Type:       "Feature",
+				Geometry:   GeoJSONPoint(*l.LatLong),
+				Properties: props,
+			})
+		}
+	}
+	return NewGeoJSONFeatureCollection(features)
+}
+
+// MarshalGeoJSON encodes the results as GeoJSON feature collection.
+func (res *GeocodeAddressResponse) MarshalGeoJSON() ([]byte, error) {
+	return json.Marshal(res.ToFeatureCollection())
+}
+
+// ToFeature returns the entry as Point feature. The properties carry the
+// normalized address along with the importance and OSM identifiers, the
+// bbox the bounding box of the place.
+func (s *NominatimSearchResponseEntry) ToFeature() *GeoJSONFeature {
+	props := map[string]interface{}{}
+	if s.Importance != 0 {
+		props["importance"] = s.Importance
+	}
+	setNonEmpty(props,
+		"displayName", s.DisplayName,
+		"placeId", s.PlaceID,
+		"osmType", s.OSMType,
+		"osmId", s.OSMId,
+		"class", s.Class,
+		"type", s.Type,
+	)
+	if a := s.Address; a != nil {
+		street := a.Road
+		if street == "" {
+			street = a.Pedestrian
+		}
+		if street != "" && a.HouseNumber != "" {
+			street = a.HouseNumber + " " + street
+		}
+		setNonEmpty(props,
+			"street", street,
+			"neighborhood", a.Neighbourhood,
+			"city", a.City,
+			"county", a.County,
+			"state", a.State,
+			"postalCode", a.PostCode,
+			"country", a.Country,
+			"countryCode", strings.ToUpper(a.CountryCode),
+		)
+	}
+
+	f := &GeoJSONFeature{
+		Type:       "Feature",
+		Geometry:   GeoJSONPoint(GeoPoint{Latitude: s.Latitude, Longitude: s.Longitude}),
+		Properties: props,
+	}
+	// nominatim orders its box south, north, west, east
+	if b := s.BoundingBox; len(b) == 4 {
+		f.BBox = []float64{b[2], b[0], b[3], b[1]}
+	}
+	return f
+}
+
+// ToFeatureCollection returns one Point feature per result.
+func (res *NominatimSearchResponse) ToFeatureCollection() *GeoJSONFeatureCollection {
+	features := make([]*GeoJSONFeature, len(res.Results))
+	for i, entry := range res.Results {
+		features[i] = entry.ToFeature()
+	}
+	return NewGeoJSONFeatureCollection(features)
+}
+
+// MarshalGeoJSON encodes the results as GeoJSON feature collection.
+func (res *NominatimSearchResponse) MarshalGeoJSON() ([]byte, error) {
+	return json.Marshal(res.ToFeatureCollection())
+}
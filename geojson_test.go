@@ -0,0 +1,42 @@
+package mapquest
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestNewGeoJSONFeatureCollection(t *testing.T) {
+	features := []*GeoJSONFeature{
+		{Type: "Feature", Geometry: GeoJSONPoint(GeoPoint{Latitude: 40, Longitude: -76})},
+		// GeoJSON allows features without a geometry
+		{Type: "Feature", Properties: map[string]interface{}{"name": "unlocated"}},
+		{Type: "Feature", Geometry: GeoJSONLineString([]GeoPoint{{Latitude: 0, Longitude: 0}, {Latitude: 60, Longitude: 10}})},
+		{Type: "Feature", Geometry: GeoJSONPoint(GeoPoint{Latitude: 41, Longitude: -75})},
+	}
+
+	fc := NewGeoJSONFeatureCollection(features)
+	if want := []float64{-76, 40, -75, 41}; !reflect.DeepEqual(fc.BBox, want) {
+		t.Errorf("got bbox %v, want %v", fc.BBox, want)
+	}
+
+	data, err := json.Marshal(fc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded struct {
+		Features []struct {
+			Geometry json.RawMessage `json:"geometry"`
+		} `json:"features"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if got := string(decoded.Features[1].Geometry); got != "null" {
+		t.Errorf("got geometry %s, want null", got)
+	}
+
+	if fc := NewGeoJSONFeatureCollection(nil); fc.Features == nil || fc.BBox != nil {
+		t.Errorf("got %+v for no features", fc)
+	}
+}
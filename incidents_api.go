@@ -0,0 +1,184 @@
+package mapquest
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/go-querystring/query"
+)
+
+const (
+	IncidentsPrefix  = "traffic"
+	IncidentsVersion = "v2"
+)
+
+// IncidentsAPI enables users to request traffic incidents via the
+// MapQuest API.
+// See https://developer.mapquest.com/documentation/traffic-api/incidents/get/ for details.
+type IncidentsAPI struct {
+	c *Client
+}
+
+// SimpleIncidents returns all incidents within box.
+func (api *IncidentsAPI) SimpleIncidents(box *BoundingBox) (*IncidentsResponse, error) {
+	return api.Incidents(&IncidentsRequest{BoundingBox: box})
+}
+
+// Incidents returns the incidents matching req.
+func (api *IncidentsAPI) Incidents(req *IncidentsRequest) (*IncidentsResponse, error) {
+	return api.IncidentsContext(context.Background(), req)
+}
+
+// IncidentsContext is like Incidents, but binds the request to ctx.
+func (api *IncidentsAPI) IncidentsContext(ctx context.Context, req *IncidentsRequest) (*IncidentsResponse, error) {
+	q, err := query.Values(req)
+	if err != nil {
+		return nil, err
+	}
+
+	q.Set("key", api.c.key)
+	q.Set("outFormat", "json")
+	u := api.c.apiURL(ServiceIncidents, IncidentsVersion, "incidents")
+	u.RawQuery = q.Encode()
+
+	res := new(IncidentsResponse)
+	if err := api.c.getJSON(ctx, ServiceIncidents, u, res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+type IncidentFilter string
+
+const (
+	IncidentFilterConstruction IncidentFilter = "construction"
+	IncidentFilterIncidents    IncidentFilter = "incidents"
+	IncidentFilterCongestion   IncidentFilter = "congestion"
+	IncidentFilterEvent        IncidentFilter = "event"
+)
+
+type IncidentsRequest struct {
+	BoundingBox *BoundingBox     `url:"boundingBox"`
+	Filters     []IncidentFilter `url:"filters,comma,omitempty"` // all incidents if empty
+}
+
+type IncidentsResponse struct {
+	Info      *ResponseInfo `json:"info,omitempty"`
+	Incidents []*Incident   `json:"incidents,omitempty"`
+}
+
+func (res *IncidentsResponse) responseInfo() *ResponseInfo {
+	return res.Info
+}
+
+type IncidentType int
+
+const (
+	IncidentTypeConstruction IncidentType = 1
+	IncidentTypeEvent        IncidentType = 2
+	IncidentTypeCongestion   IncidentType = 3
+	IncidentTypeIncident     IncidentType = 4
+)
+
+type Incident struct {
+	ID   string
+	Type IncidentType
+	// Severity ranges from 0 (minimal impact) to 4 (major impact).
+	Severity  int
+	EventCode int
+	Location  GeoPoint
+	StartTime time.Time
+	EndTime   time.Time
+	// Impacting is set if the incident affects traffic flow.
+	Impacting        bool
+	ShortDescription string
+	Description      string
+	// DelayFromFreeFlow and DelayFromTypical are delays in minutes.
+	DelayFromFreeFlow float64
+	DelayFromTypical  float64
+	// Distance is the length of the affected road, in miles.
+	Distance float64
+	IconURL  string
+}
+
+// incidentTimeLayouts are the layouts incident times are reported in.
+var incidentTimeLayouts = []string{time.RFC3339, "2006-01-02T15:04:05"}
+
+func (s *Incident) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		ID                json.Number  `json:"id"`
+		Type              IncidentType `json:"type"`
+		Severity          int          `json:"severity"`
+		EventCode         int          `json:"eventCode"`
+		Latitude          float64      `json:"lat"`
+		Longitude         float64      `json:"lng"`
+		StartTime         string       `json:"startTime"`
+		EndTime           string       `json:"endTime"`
+		Impacting         bool         `json:"impacting"`
+		ShortDesc         string       `json:"shortDesc"`
+		FullDesc          string       `json:"fullDesc"`
+		DelayFromFreeFlow float64      `json:"delayFromFreeFlow"`
+		DelayFromTypical  float64      `json:"delayFromTypical"`
+		Distance          float64      `json:"distance"`
+		IconURL           string       `json:"iconURL"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*s = Incident{
+		ID:                raw.ID.String(),
+		Type:              raw.Type,
+		Severity:          raw.Severity,
+		EventCode:         raw.EventCode,
+		Location:          GeoPoint{Latitude: raw.Latitude, Longitude: raw.Longitude},
+		Impacting:         raw.Impacting,
+		ShortDescription:  raw.ShortDesc,
+		Description:       raw.FullDesc,
+		DelayFromFreeFlow: raw.DelayFromFreeFlow,
+		DelayFromTypical:  raw.DelayFromTypical,
+		Distance:          raw.Distance,
+		IconURL:           raw.IconURL,
+	}
+	s.StartTime = parseIncidentTime(raw.StartTime)
+	s.EndTime = parseIncidentTime(raw.EndTime)
+	return nil
+}
+
+func parseIncidentTime(v string) time.Time {
+	for _, layout := range incidentTimeLayouts {
+		if t, err := time.Parse(layout, v); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// incidentMarkerColors are the marker colors by severity.
+var incidentMarkerColors = []string{"22aa22", "aaaa22", "ff9900", "ff3300", "cc0000"}
+
+// StaticMapLocation returns a marker for the incident, colored by its
+// severity.
+func (s *Incident) StaticMapLocation() StaticMapLocation {
+	severity := s.Severity
+	if severity < 0 {
+		severity = 0
+	} else if severity >= len(incidentMarkerColors) {
+		severity = len(incidentMarkerColors) - 1
+	}
+
+	return StaticMapLocation{
+		Location: s.Location.String(),
+		Marker:   "marker-" + incidentMarkerColors[severity],
+	}
+}
+
+// AddIncidents adds a marker for each of the incidents to the locations
+// of the static map.
+func (req *StaticMapRequest) AddIncidents(incidents ...*Incident) {
+	for _, incident := range incidents {
+		req.Locations = append(req.Locations, incident.StaticMapLocation())
+	}
+}
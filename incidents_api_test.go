@@ -0,0 +1,92 @@
+package mapquest_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cking/mapquest"
+	"github.com/cking/mapquest/mapquesttest"
+)
+
+const pathIncidents = "/traffic/v2/incidents"
+
+func TestIncidents(t *testing.T) {
+	srv := mapquesttest.NewServer()
+	defer srv.Close()
+	srv.Enqueue(pathIncidents, &mapquesttest.Response{Body: []byte(`{
+		"info": {"statuscode": 0},
+		"incidents": [
+			{"id": 4173577352131398500, "type": 1, "severity": 2, "eventCode": 701, "lat": 39.93, "lng": -105.01,
+			 "startTime": "2024-03-04T06:00:00", "endTime": "2024-03-29T17:00:00", "impacting": false,
+			 "shortDesc": "Lane closed", "fullDesc": "Lane closed due to roadwork.", "distance": 0.61,
+			 "delayFromFreeFlow": 0, "delayFromTypical": 0, "iconURL": "https://api.mqcdn.com/mqtraffic/const_mod.png"},
+			{"id": "8011239", "type": 4, "severity": 4, "lat": 39.74, "lng": -104.99,
+			 "startTime": "2024-03-05T08:12:00-07:00", "impacting": true, "delayFromFreeFlow": 12.5}
+		]
+	}`)})
+
+	box := &mapquest.BoundingBox{
+		TopLeft:     mapquest.GeoPoint{Latitude: 40, Longitude: -105.1},
+		BottomRight: mapquest.GeoPoint{Latitude: 39.7, Longitude: -104.9},
+	}
+	res, err := srv.Client("key").Incidents().IncidentsContext(context.Background(), &mapquest.IncidentsRequest{
+		BoundingBox: box,
+		Filters:     []mapquest.IncidentFilter{mapquest.IncidentFilterConstruction, mapquest.IncidentFilterIncidents},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	q := srv.RequestsTo(pathIncidents)[0].Query
+	if q.Get("boundingBox") != "40.000000,-105.100000,39.700000,-104.900000" || q.Get("filters") != "construction,incidents" {
+		t.Errorf("got query %s", q.Encode())
+	}
+
+	if len(res.Incidents) != 2 {
+		t.Fatalf("got %d incidents, want 2", len(res.Incidents))
+	}
+	construction, accident := res.Incidents[0], res.Incidents[1]
+	if construction.ID != "4173577352131398500" || construction.Type != mapquest.IncidentTypeConstruction || construction.EventCode != 701 {
+		t.Errorf("got %+v", construction)
+	}
+	if want := time.Date(2024, 3, 4, 6, 0, 0, 0, time.UTC); !construction.StartTime.Equal(want) {
+		t.Errorf("got start time %v, want %v", construction.StartTime, want)
+	}
+	if construction.Description != "Lane closed due to roadwork." || construction.Distance != 0.61 {
+		t.Errorf("got %+v", construction)
+	}
+	if accident.ID != "8011239" || !accident.Impacting || accident.DelayFromFreeFlow != 12.5 || !accident.EndTime.IsZero() {
+		t.Errorf("got %+v", accident)
+	}
+	if want := time.Date(2024, 3, 5, 15, 12, 0, 0, time.UTC); !accident.StartTime.Equal(want) {
+		t.Errorf("got start time %v, want %v", accident.StartTime, want)
+	}
+	if accident.Location != (mapquest.GeoPoint{Latitude: 39.74, Longitude: -104.99}) {
+		t.Errorf("got location %v", accident.Location)
+	}
+}
+
+func TestIncidentStaticMapLocation(t *testing.T) {
+	for severity, want := range map[int]string{
+		-1: "marker-22aa22",
+		0:  "marker-22aa22",
+		1:  "marker-aaaa22",
+		2:  "marker-ff9900",
+		3:  "marker-ff3300",
+		4:  "marker-cc0000",
+		5:  "marker-cc0000",
+	} {
+		incident := &mapquest.Incident{Severity: severity, Location: mapquest.GeoPoint{Latitude: 39.74, Longitude: -104.99}}
+		got := incident.StaticMapLocation()
+		if got.Marker != want || got.Location != "39.740000,-104.990000" {
+			t.Errorf("severity %d: got %+v, want marker %s", severity, got, want)
+		}
+	}
+
+	req := new(mapquest.StaticMapRequest)
+	req.AddIncidents(&mapquest.Incident{Severity: 4}, &mapquest.Incident{Severity: 0})
+	if len(req.Locations) != 2 || req.Locations[0].Marker != "marker-cc0000" || req.Locations[1].Marker != "marker-22aa22" {
+		t.Errorf("got locations %+v", req.Locations)
+	}
+}
@@ -1,9 +1,16 @@
 package mapquest
 
 import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
 	"net/http"
 	"net/url"
 	"strings"
+	"sync/atomic"
+	"time"
 )
 
 const (
@@ -11,30 +18,37 @@ const (
 	UserAgent = "MapQuest Open Data API Google Go Client v0.1"
 )
 
-func apiURL(path ...string) *url.URL {
-	u := new(url.URL)
-	u.Scheme = "https"
-	u.Host = Host
-	u.Path = "/" + strings.Join(path, "/")
-
-	return u
-}
-
 // Client is the entry point to all services of the MapQuest Open Data API.
 // See https://developer.mapquest.com/documentation/open/ for details about
 // what you can do with the MapQuest API.
 type Client struct {
-	httpClient *http.Client
-	key        string
+	httpClient  *http.Client
+	key         string
+	baseURL     *url.URL
+	serviceURLs map[Service]*url.URL
+	userAgent   string
+	timeout     time.Duration
+	retryPolicy *RetryPolicy
+	limiters    map[Service]*RateLimiter
+	cache       Cache
+
+	cacheHits   atomic.Uint64
+	cacheMisses atomic.Uint64
 }
 
 // NewClient creates a new client for accessing the MapQuest API. You need
-// to specify your AppKey here.
-func NewClient(key string) *Client {
-	return &Client{
+// to specify your AppKey here. Further settings can be passed as options.
+func NewClient(key string, options ...Option) *Client {
+	c := &Client{
 		key:        key,
 		httpClient: http.DefaultClient,
+		baseURL:    &url.URL{Scheme: "https", Host: Host},
+		userAgent:  UserAgent,
+	}
+	for _, option := range options {
+		option(c)
 	}
+	return c
 }
 
 // SetHTTPClient allows the caller to specify a special http.Client for
@@ -58,3 +72,244 @@ func (c *Client) HTTPClient() *http.Client {
 func (c *Client) StaticMap() *StaticMapAPI {
 	return &StaticMapAPI{c: c}
 }
+
+// Geocoding gives access to the MapQuest geocoding API
+// described here: https://developer.mapquest.com/documentation/open/geocoding-api/
+func (c *Client) Geocoding() *GeocodingAPI {
+	return &GeocodingAPI{c: c}
+}
+
+// Nominatim gives access to the MapQuest nominatim API
+// described here: https://developer.mapquest.com/documentation/open/nominatim-search/
+func (c *Client) Nominatim() *NominatimAPI {
+	return &NominatimAPI{c: c}
+}
+
+// Route gives access to the MapQuest directions API
+// described here: https://developer.mapquest.com/documentation/open/directions-api/
+func (c *Client) Route() *RouteAPI {
+	return &RouteAPI{c: c}
+}
+
+// RouteMatrix gives access to the MapQuest route matrix API
+// described here: https://developer.mapquest.com/documentation/open/directions-api/route-matrix/post/
+func (c *Client) RouteMatrix() *RouteMatrixAPI {
+	return &RouteMatrixAPI{c: c}
+}
+
+// Elevation gives access to the MapQuest elevation API
+// described here: https://developer.mapquest.com/documentation/open/elevation-api/
+func (c *Client) Elevation() *ElevationAPI {
+	return &ElevationAPI{c: c}
+}
+
+// Incidents gives access to the MapQuest traffic incidents API
+// described here: https://developer.mapquest.com/documentation/traffic-api/
+func (c *Client) Incidents() *IncidentsAPI {
+	return &IncidentsAPI{c: c}
+}
+
+// apiURL returns the URL of an endpoint of service. The path elements are
+// appended to the base URL of the service.
+func (c *Client) apiURL(service Service, path ...string) *url.URL {
+	base := c.baseURL
+	if u, ok := c.serviceURLs[service]; ok {
+		base = u
+	}
+
+	u := *base
+	u.Path = strings.TrimSuffix(base.Path, "/") + "/" + string(service) + "/" + strings.Join(path, "/")
+	u.RawPath = ""
+	u.RawQuery = ""
+	u.Fragment = ""
+
+	return &u
+}
+
+// get issues a GET request for u, bound to ctx, and returns the raw response.
+// Responses not indicating success are turned into an *APIError. The caller
+// is responsible for closing the response body.
+func (c *Client) get(ctx context.Context, service Service, u *url.URL) (*http.Response, error) {
+	return c.do(ctx, service, "GET", u, nil, "")
+}
+
+// do issues a request for u, bound to ctx, retrying it according to the
+// retry policy of the client. Every attempt is subject to the rate limit of
+// service. body is sent with every attempt.
+func (c *Client) do(ctx context.Context, service Service, method string, u *url.URL, body []byte, contentType string) (*http.Response, error) {
+	cancel := context.CancelFunc(func() {})
+	if _, ok := ctx.Deadline(); !ok && c.timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+	}
+
+	policy := c.retryPolicy
+	for attempt := 1; ; attempt++ {
+		var bodyReader io.Reader
+		if body != nil {
+			bodyReader = bytes.NewReader(body)
+		}
+		httpRequest, err := http.NewRequestWithContext(ctx, method, u.String(), bodyReader)
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		httpRequest.Header.Set("User-Agent", c.userAgent)
+		if contentType != "" {
+			httpRequest.Header.Set("Content-Type", contentType)
+		}
+
+		if err := c.limiters[service].Wait(ctx); err != nil {
+			cancel()
+			return nil, err
+		}
+
+		final := attempt >= policy.attempts()
+		httpResponse, err := c.httpClient.Do(httpRequest)
+		if err != nil {
+			if final || !policy.retryError(ctx, err) {
+				cancel()
+				return nil, err
+			}
+			if err := sleep(ctx, policy.backoff(attempt)); err != nil {
+				cancel()
+				return nil, err
+			}
+			continue
+		}
+
+		if !final && policy.retryStatus(httpResponse.StatusCode) {
+			delay, ok := retryAfter(httpResponse)
+			if !ok {
+				delay = policy.backoff(attempt)
+			}
+			io.Copy(io.Discard, io.LimitReader(httpResponse.Body, maxErrorBodySize))
+			httpResponse.Body.Close()
+			if err := sleep(ctx, delay); err != nil {
+				cancel()
+				return nil, err
+			}
+			continue
+		}
+
+		if err := checkResponse(httpResponse); err != nil {
+			httpResponse.Body.Close()
+			cancel()
+			return nil, err
+		}
+
+		httpResponse.Body = &cancelReadCloser{ReadCloser: httpResponse.Body, cancel: cancel}
+		return httpResponse, nil
+	}
+}
+
+// getImage is like get, but also turns successful responses not carrying
+// an image, e.g. textual error pages, into an *APIError.
+func (c *Client) getImage(ctx context.Context, service Service, u *url.URL) (io.ReadCloser, error) {
+	httpResponse, err := c.get(ctx, service, u)
+	if err != nil {
+		return nil, err
+	}
+
+	// sniff the body if the server did not declare its type
+	body := bufio.NewReader(httpResponse.Body)
+	contentType := httpResponse.Header.Get("Content-Type")
+	if contentType == "" {
+		head, _ := body.Peek(512)
+		contentType = http.DetectContentType(head)
+	}
+	if !strings.HasPrefix(contentType, "image/") {
+		err := readAPIError(httpResponse, body)
+		httpResponse.Body.Close()
+		return nil, err
+	}
+
+	return struct {
+		io.Reader
+		io.Closer
+	}{body, httpResponse.Body}, nil
+}
+
+// cancelReadCloser releases the context of a request once its response
+// body is closed.
+type cancelReadCloser struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (r *cancelReadCloser) Close() error {
+	err := r.ReadCloser.Close()
+	r.cancel()
+	return err
+}
+
+// getJSON issues a GET request for u, bound to ctx, and decodes the JSON
+// response body into v. If v reports a failure, an *APIError is returned.
+func (c *Client) getJSON(ctx context.Context, service Service, u *url.URL, v interface{}) error {
+	httpResponse, err := c.get(ctx, service, u)
+	if err != nil {
+		return err
+	}
+	defer httpResponse.Body.Close()
+
+	if err := json.NewDecoder(httpResponse.Body).Decode(v); err != nil {
+		return err
+	}
+	return checkResponseBody(httpResponse, v)
+}
+
+// getCachedJSON is like getJSON, but consults the cache of the client
+// first. Successful responses are added to the cache, responses reporting
+// a failure are not.
+func (c *Client) getCachedJSON(ctx context.Context, service Service, u *url.URL, v interface{}) error {
+	if c.cache == nil {
+		return c.getJSON(ctx, service, u, v)
+	}
+
+	key := cacheKey(service, u)
+	if body, ok := c.cache.Get(key); ok {
+		c.cacheHits.Add(1)
+		return json.Unmarshal(body, v)
+	}
+	c.cacheMisses.Add(1)
+
+	httpResponse, err := c.get(ctx, service, u)
+	if err != nil {
+		return err
+	}
+	defer httpResponse.Body.Close()
+
+	body, err := io.ReadAll(httpResponse.Body)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return err
+	}
+	if err := checkResponseBody(httpResponse, v); err != nil {
+		return err
+	}
+
+	c.cache.Set(key, body)
+	return nil
+}
+
+// postJSON issues a POST request for u, bound to ctx, with body encoded as
+// JSON, and decodes the JSON response body into v. If v reports a failure,
+// an *APIError is returned.
+func (c *Client) postJSON(ctx context.Context, service Service, u *url.URL, body, v interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	httpResponse, err := c.do(ctx, service, "POST", u, data, "application/json")
+	if err != nil {
+		return err
+	}
+	defer httpResponse.Body.Close()
+
+	if err := json.NewDecoder(httpResponse.Body).Decode(v); err != nil {
+		return err
+	}
+	return checkResponseBody(httpResponse, v)
+}
@@ -0,0 +1,270 @@
+package mapquesttest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// scrubbedKey replaces the access key in recorded fixtures.
+const scrubbedKey = "SCRUBBED"
+
+// Mode selects whether a Recorder records or replays interactions.
+type Mode int
+
+const (
+	// ModeReplay serves responses from the fixture file.
+	ModeReplay Mode = iota
+	// ModeRecord forwards requests to the real API and records them.
+	ModeRecord
+)
+
+// Interaction is a recorded request/response pair.
+type Interaction struct {
+	Request struct {
+		Method string `json:"method"`
+		// URL is the request URL without the key parameter.
+		URL  string `json:"url"`
+		Body string `json:"body,omitempty"`
+	} `json:"request"`
+	Response struct {
+		Status int         `json:"status"`
+		Header http.Header `json:"header,omitempty"`
+		// Body is the response body if it is text. Binary bodies, like
+		// images, are stored in BinaryBody instead, as JSON strings cannot
+		// hold them.
+		Body       string `json:"body,omitempty"`
+		BinaryBody []byte `json:"binaryBody,omitempty"`
+	} `json:"response"`
+}
+
+// Recorder is an http.RoundTripper recording interactions with the
+// MapQuest API to a fixture file and replaying them later. Use it with
+// Client.SetHTTPClient:
+//
+//	rec, err := mapquesttest.NewRecorder("testdata/geocode.json", mapquesttest.ModeReplay)
+//	...
+//	client.SetHTTPClient(rec.HTTPClient())
+//
+// The key parameter is scrubbed from recorded URLs, response headers and
+// text response bodies. Binary response bodies are recorded verbatim.
+// Requests are matched by method, path, body and query, regardless of the
+// order of the query parameters.
+type Recorder struct {
+	// Strict makes unmatched requests fail in replay mode. Otherwise they
+	// are forwarded to Transport.
+	Strict bool
+	// Transport performs the requests to record. It defaults to
+	// http.DefaultTransport.
+	Transport http.RoundTripper
+
+	path string
+	mode Mode
+
+	mu           sync.Mutex
+	interactions []*Interaction
+	replayed     []bool
+}
+
+// NewRecorder creates a Recorder for the fixture file at path. In replay
+// mode, the file is loaded and must exist. In record mode, call Save to
+// write the file. Replay is strict by default.
+func NewRecorder(path string, mode Mode) (*Recorder, error) {
+	r := &Recorder{path: path, mode: mode, Strict: true}
+	if mode == ModeRecord {
+		return r, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &r.interactions); err != nil {
+		return nil, fmt.Errorf("mapquesttest: invalid fixture %s: %v", path, err)
+	}
+	r.replayed = make([]bool, len(r.interactions))
+	return r, nil
+}
+
+// HTTPClient returns an http.Client using the recorder as transport.
+func (r *Recorder) HTTPClient() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// Interactions returns the interactions recorded or loaded so far.
+func (r *Recorder) Interactions() []*Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*Interaction(nil), r.interactions...)
+}
+
+// Save writes the recorded interactions to the fixture file.
+func (r *Recorder) Save() error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+
+	r.mu.Lock()
+	err := enc.Encode(r.interactions)
+	r.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, buf.Bytes(), 0644)
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	if r.mode == ModeRecord {
+		return r.record(req, body)
+	}
+
+	if res := r.replay(req, body); res != nil {
+		return res, nil
+	}
+	if r.Strict {
+		return nil, fmt.Errorf("mapquesttest: no recorded interaction for %s %s", req.Method, scrubURL(req))
+	}
+	return r.transport().RoundTrip(req)
+}
+
+func (r *Recorder) transport() http.RoundTripper {
+	if r.Transport != nil {
+		return r.Transport
+	}
+	return http.DefaultTransport
+}
+
+func (r *Recorder) record(req *http.Request, body []byte) (*http.Response, error) {
+	res, err := r.transport().RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	resBody, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(resBody))
+
+	i := new(Interaction)
+	i.Request.Method = req.Method
+	i.Request.URL = scrubURL(req)
+	i.Request.Body = string(body)
+	i.Response.Status = res.StatusCode
+	// responses may embed the key, e.g. in map URLs of geocoding results;
+	// binary bodies are stored as they are, as replacing bytes would
+	// corrupt them
+	scrub := func(s string) string { return s }
+	if key := req.URL.Query().Get("key"); key != "" {
+		scrub = func(s string) string { return strings.ReplaceAll(s, key, scrubbedKey) }
+	}
+	i.Response.Header = make(http.Header, len(res.Header))
+	for name, values := range res.Header {
+		for _, v := range values {
+			i.Response.Header.Add(name, scrub(v))
+		}
+	}
+	if utf8.Valid(resBody) {
+		i.Response.Body = scrub(string(resBody))
+	} else {
+		i.Response.BinaryBody = resBody
+	}
+
+	r.mu.Lock()
+	r.interactions = append(r.interactions, i)
+	r.mu.Unlock()
+
+	return res, nil
+}
+
+// replay returns the response of the first interaction matching req that
+// was not replayed yet. If all matching interactions were replayed, the
+// last one is replayed again. It returns nil if nothing matches.
+func (r *Recorder) replay(req *http.Request, body []byte) *http.Response {
+	want := matchKey(req.Method, scrubURL(req), string(body))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	match := -1
+	for i, interaction := range r.interactions {
+		if matchKey(interaction.Request.Method, interaction.Request.URL, interaction.Request.Body) != want {
+			continue
+		}
+		match = i
+		if !r.replayed[i] {
+			break
+		}
+	}
+	if match < 0 {
+		return nil
+	}
+	r.replayed[match] = true
+
+	i := r.interactions[match]
+	resBody := []byte(i.Response.Body)
+	if i.Response.BinaryBody != nil {
+		resBody = i.Response.BinaryBody
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", i.Response.Status, http.StatusText(i.Response.Status)),
+		StatusCode:    i.Response.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        i.Response.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(resBody)),
+		ContentLength: int64(len(resBody)),
+		Request:       req,
+	}
+}
+
+// scrubURL returns the URL of req without the key parameter.
+func scrubURL(req *http.Request) string {
+	u := *req.URL
+	q := u.Query()
+	q.Del("key")
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// matchKey normalizes a request for matching. The host is left out, so
+// fixtures recorded against one base URL replay against any other.
+func matchKey(method, rawurl, body string) string {
+	path, query := rawurl, ""
+	if i := strings.Index(rawurl, "?"); i >= 0 {
+		path, query = rawurl[:i], rawurl[i+1:]
+	}
+	if i := strings.Index(path, "://"); i >= 0 {
+		path = path[i+3:]
+		if j := strings.Index(path, "/"); j >= 0 {
+			path = path[j:]
+		} else {
+			path = "/"
+		}
+	}
+	if q, err := url.ParseQuery(query); err == nil {
+		q.Del("key")
+		query = q.Encode()
+	}
+	return method + " " + path + "?" + query + "\n" + body
+}
@@ -0,0 +1,123 @@
+package mapquesttest_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cking/mapquest"
+	"github.com/cking/mapquest/mapquesttest"
+)
+
+func fetchMap(t *testing.T, client *mapquest.Client) []byte {
+	t.Helper()
+	r, err := client.StaticMap().MapReaderContext(context.Background(), &mapquest.StaticMapRequest{
+		Size:   &mapquest.StaticMapSize{Width: 20, Height: 10},
+		Center: "Lancaster, PA",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestRecorderReplaysImages(t *testing.T) {
+	srv := mapquesttest.NewServer()
+	defer srv.Close()
+	fixture := filepath.Join(t.TempDir(), "staticmap.json")
+
+	rec, err := mapquesttest.NewRecorder(fixture, mapquesttest.ModeRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := srv.Client("secret")
+	client.SetHTTPClient(rec.HTTPClient())
+	recorded := fetchMap(t, client)
+	if !bytes.HasPrefix(recorded, []byte("\x89PNG")) {
+		t.Fatalf("recorded body is not a PNG: % x", recorded[:8])
+	}
+	if err := rec.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	rec, err = mapquesttest.NewRecorder(fixture, mapquesttest.ModeReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	interactions := rec.Interactions()
+	if len(interactions) != 1 {
+		t.Fatalf("got %d interactions, want 1", len(interactions))
+	}
+	if strings.Contains(interactions[0].Request.URL, "secret") {
+		t.Errorf("key not scrubbed from %s", interactions[0].Request.URL)
+	}
+
+	// the server is not needed to replay
+	srv.Close()
+	client = mapquest.NewClient("secret", mapquest.WithHTTPClient(rec.HTTPClient()))
+	if replayed := fetchMap(t, client); !bytes.Equal(replayed, recorded) {
+		t.Errorf("replayed body differs from recorded body:\n% x\n% x", replayed, recorded)
+	}
+}
+
+func TestRecorderScrubsTextBodies(t *testing.T) {
+	srv := mapquesttest.NewServer()
+	defer srv.Close()
+	srv.Enqueue(mapquesttest.PathGeocodeAddress, mapquesttest.TextResponse(200, `{"info":{"messages":["key secret"]}}`))
+	fixture := filepath.Join(t.TempDir(), "geocode.json")
+
+	rec, err := mapquesttest.NewRecorder(fixture, mapquesttest.ModeRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := srv.Client("secret")
+	client.SetHTTPClient(rec.HTTPClient())
+	if _, err := client.Geocoding().SimpleAddress("Lancaster, PA", 1); err != nil {
+		t.Fatal(err)
+	}
+
+	i := rec.Interactions()[0]
+	if want := `{"info":{"messages":["key SCRUBBED"]}}`; i.Response.Body != want || i.Response.BinaryBody != nil {
+		t.Errorf("got body %q, binary body %q, want %q", i.Response.Body, i.Response.BinaryBody, want)
+	}
+}
+
+func TestRecorderKeepsBinaryBodies(t *testing.T) {
+	srv := mapquesttest.NewServer()
+	defer srv.Close()
+	image := []byte("\x89PNG\r\n\x1a\n\xffsecret\xfe")
+	srv.Enqueue(mapquesttest.PathStaticMap, &mapquesttest.Response{
+		Header: http.Header{
+			"Content-Type": {"image/png"},
+			"Link":         {"<https://www.mapquestapi.com/staticmap/v5/map?key=secret>"},
+		},
+		Body: image,
+	})
+
+	rec, err := mapquesttest.NewRecorder(filepath.Join(t.TempDir(), "staticmap.json"), mapquesttest.ModeRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := srv.Client("secret")
+	client.SetHTTPClient(rec.HTTPClient())
+	if got := fetchMap(t, client); !bytes.Equal(got, image) {
+		t.Errorf("got body % x, want % x", got, image)
+	}
+
+	i := rec.Interactions()[0]
+	if !bytes.Equal(i.Response.BinaryBody, image) || i.Response.Body != "" {
+		t.Errorf("got binary body % x, want % x", i.Response.BinaryBody, image)
+	}
+	if got, want := i.Response.Header.Get("Link"), "<https://www.mapquestapi.com/staticmap/v5/map?key=SCRUBBED>"; got != want {
+		t.Errorf("got header %q, want %q", got, want)
+	}
+}
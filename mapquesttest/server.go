@@ -0,0 +1,337 @@
+/*
+Package mapquesttest provides an in-process fake of the MapQuest API for
+tests, so they need neither network access nor a real access key.
+
+	srv := mapquesttest.NewServer()
+	defer srv.Close()
+
+	client := srv.Client("test-key")
+	srv.Enqueue(mapquesttest.PathGeocodeAddress, mapquesttest.StatusResponse(403, "quota exceeded"))
+	_, err := client.Geocoding().SimpleAddress("Lancaster, PA", 1)
+
+Every endpoint answers with a canned response unless a response was
+enqueued for it. All requests are recorded for later assertions.
+*/
+package mapquesttest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cking/mapquest"
+)
+
+// Paths of the endpoints served by Server.
+const (
+	PathGeocodeAddress   = "/geocoding/v1/address"
+	PathGeocodeReverse   = "/geocoding/v1/reverse"
+	PathGeocodeBatch     = "/geocoding/v1/batch"
+	PathNominatimSearch  = "/nominatim/v1/search.php"
+	PathNominatimReverse = "/nominatim/v1/reverse.php"
+	PathStaticMap        = "/staticmap/v5/map"
+)
+
+// DefaultPoint is the coordinate of all canned results.
+var DefaultPoint = mapquest.GeoPoint{Latitude: 40.053116, Longitude: -76.313603}
+
+// Response is a scripted response of the Server.
+type Response struct {
+	// Status is the HTTP status code. It defaults to 200.
+	Status int
+	Header http.Header
+	Body   []byte
+	// Delay postpones the response, e.g. to trigger client timeouts.
+	Delay time.Duration
+}
+
+// JSONResponse returns a response with v encoded as JSON.
+func JSONResponse(v interface{}) *Response {
+	body, err := json.Marshal(v)
+	if err != nil {
+		panic(fmt.Sprintf("mapquesttest: cannot encode response: %v", err))
+	}
+	return &Response{
+		Header: http.Header{"Content-Type": {"application/json"}},
+		Body:   body,
+	}
+}
+
+// StatusResponse returns a response with the HTTP status code status and
+// an info block carrying status and message, like MapQuest reports errors.
+func StatusResponse(status int, message string) *Response {
+	res := JSONResponse(map[string]interface{}{
+		"info": &mapquest.ResponseInfo{StatusCode: status, Messages: []string{message}},
+	})
+	res.Status = status
+	return res
+}
+
+// TextResponse returns a plain text response with the HTTP status code
+// status, like the error pages of the static map endpoint.
+func TextResponse(status int, text string) *Response {
+	return &Response{
+		Status: status,
+		Header: http.Header{"Content-Type": {"text/plain; charset=utf-8"}},
+		Body:   []byte(text),
+	}
+}
+
+// MalformedJSONResponse returns a successful response with a truncated
+// JSON body.
+func MalformedJSONResponse() *Response {
+	return &Response{
+		Header: http.Header{"Content-Type": {"application/json"}},
+		Body:   []byte(`{"info":{"statuscode":0},"results":[{`),
+	}
+}
+
+// WithDelay returns a copy of r delayed by d.
+func (r *Response) WithDelay(d time.Duration) *Response {
+	c := *r
+	c.Delay = d
+	return &c
+}
+
+// Request is a request recorded by the Server.
+type Request struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+}
+
+// Server is an httptest.Server implementing the geocoding, nominatim and
+// static map endpoints of the MapQuest API.
+type Server struct {
+	*httptest.Server
+
+	// Key, if set, is the only access key accepted. Requests with any
+	// other key are rejected with 403.
+	Key string
+
+	mu       sync.Mutex
+	queues   map[string][]*Response
+	requests []*Request
+}
+
+// NewServer starts a Server. Call Close when done.
+func NewServer() *Server {
+	s := &Server{queues: make(map[string][]*Response)}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Client returns a mapquest.Client talking to the server. Further options
+// are applied after the ones pointing the client at the server.
+func (s *Server) Client(key string, options ...mapquest.Option) *mapquest.Client {
+	base, _ := url.Parse(s.URL)
+	options = append([]mapquest.Option{
+		mapquest.WithBaseURL(base),
+		mapquest.WithHTTPClient(s.Server.Client()),
+	}, options...)
+	return mapquest.NewClient(key, options...)
+}
+
+// Enqueue scripts the next responses of the endpoint at path. Each of them
+// is served once, in order, before the endpoint returns to its canned
+// response.
+func (s *Server) Enqueue(path string, responses ...*Response) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.queues[path] = append(s.queues[path], responses...)
+}
+
+// Requests returns all requests received so far.
+func (s *Server) Requests() []*Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*Request(nil), s.requests...)
+}
+
+// RequestsTo returns the requests received so far for the endpoint at path.
+func (s *Server) RequestsTo(path string) []*Request {
+	var requests []*Request
+	for _, r := range s.Requests() {
+		if r.Path == path {
+			requests = append(requests, r)
+		}
+	}
+	return requests
+}
+
+// Reset drops all scripted responses and recorded requests.
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.queues = make(map[string][]*Response)
+	s.requests = nil
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	req := &Request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.Query(),
+		Header: r.Header.Clone(),
+		Body:   body,
+	}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, req)
+	var res *Response
+	if queue := s.queues[req.Path]; len(queue) > 0 {
+		res, s.queues[req.Path] = queue[0], queue[1:]
+	}
+	s.mu.Unlock()
+
+	if res == nil {
+		res = s.canned(req)
+	}
+
+	if res.Delay > 0 {
+		t := time.NewTimer(res.Delay)
+		defer t.Stop()
+		select {
+		case <-r.Context().Done():
+			return
+		case <-t.C:
+		}
+	}
+
+	for k, v := range res.Header {
+		w.Header()[k] = v
+	}
+	status := res.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	w.WriteHeader(status)
+	w.Write(res.Body)
+}
+
+// canned returns the default response to req.
+func (s *Server) canned(req *Request) *Response {
+	if s.Key != "" && req.Query.Get("key") != s.Key {
+		return TextResponse(http.StatusForbidden, "The AppKey submitted with this request is invalid.")
+	}
+
+	switch req.Path {
+	case PathGeocodeAddress:
+		return JSONResponse(geocodeResponse(&mapquest.GeocodeProvidedLocation{Location: req.Query.Get("location")}))
+	case PathGeocodeReverse:
+		return JSONResponse(geocodeResponse(&mapquest.GeocodeProvidedLocation{LatLong: &DefaultPoint}))
+	case PathGeocodeBatch:
+		var body struct {
+			Locations []json.RawMessage `json:"locations"`
+		}
+		if err := json.Unmarshal(req.Body, &body); err != nil {
+			return StatusResponse(http.StatusBadRequest, "Illegal argument from request: "+err.Error())
+		}
+		locations := make([]*mapquest.GeocodeProvidedLocation, len(body.Locations))
+		for i, raw := range body.Locations {
+			locations[i] = new(mapquest.GeocodeProvidedLocation)
+			if json.Unmarshal(raw, &locations[i].Location) != nil {
+				json.Unmarshal(raw, locations[i])
+			}
+		}
+		return JSONResponse(geocodeResponse(locations...))
+	case PathNominatimSearch:
+		return JSONResponse([]*mapquest.NominatimSearchResponseEntry{nominatimEntry(req.Query.Get("q"))})
+	case PathNominatimReverse:
+		return JSONResponse(nominatimEntry(""))
+	case PathStaticMap:
+		return mapResponse(req.Query.Get("size"))
+	}
+
+	return TextResponse(http.StatusNotFound, "404 page not found")
+}
+
+func geocodeResponse(provided ...*mapquest.GeocodeProvidedLocation) *mapquest.GeocodeAddressResponse {
+	res := &mapquest.GeocodeAddressResponse{Info: &mapquest.ResponseInfo{}}
+	for _, p := range provided {
+		res.Results = append(res.Results, &mapquest.GeocodeAddressResponseEntry{
+			ProvidedLocation: p,
+			Locations: []*mapquest.GeocodeAddressResponseLocationEntry{{
+				LatLong:            &DefaultPoint,
+				DisplayLatLong:     &DefaultPoint,
+				Street:             "1090 N Charlotte St",
+				PostalCode:         "17603",
+				AdminArea5:         "Lancaster",
+				AdminArea5Type:     "City",
+				AdminArea3:         "PA",
+				AdminArea3Type:     "State",
+				AdminArea1:         "US",
+				AdminArea1Type:     "Country",
+				GeocodeQuality:     "POINT",
+				GeocodeQualityCode: "P1AAA",
+			}},
+		})
+	}
+	return res
+}
+
+func nominatimEntry(query string) *mapquest.NominatimSearchResponseEntry {
+	displayName := "1090, North Charlotte Street, Lancaster, Pennsylvania, 17603, United States of America"
+	if query != "" {
+		displayName = query
+	}
+	return &mapquest.NominatimSearchResponseEntry{
+		BoundingBox: []float64{DefaultPoint.Latitude - 0.001, DefaultPoint.Latitude + 0.001, DefaultPoint.Longitude - 0.001, DefaultPoint.Longitude + 0.001},
+		Class:       "place",
+		DisplayName: displayName,
+		Importance:  0.5,
+		Latitude:    DefaultPoint.Latitude,
+		Longitude:   DefaultPoint.Longitude,
+		OSMId:       "1",
+		OSMType:     "node",
+		PlaceID:     "1",
+		Type:        "house",
+	}
+}
+
+// mapResponse renders a blank PNG of the requested size, given as
+// "width,height" with an optional "@2" suffix doubling it for retina maps.
+func mapResponse(size string) *Response {
+	width, height := 400, 400
+	size, scale, _ := strings.Cut(size, "@")
+	if parts := strings.Split(size, ","); len(parts) == 2 {
+		if w, err := strconv.Atoi(parts[0]); err == nil && w > 0 {
+			width = w
+		}
+		if h, err := strconv.Atoi(parts[1]); err == nil && h > 0 {
+			height = h
+		}
+	}
+	if scale == "2" {
+		width, height = 2*width, 2*height
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = 0xee, 0xee, 0xee, 0xff
+	}
+	img.Set(0, 0, color.Black)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return TextResponse(http.StatusInternalServerError, err.Error())
+	}
+	return &Response{
+		Header: http.Header{"Content-Type": {"image/png"}},
+		Body:   buf.Bytes(),
+	}
+}
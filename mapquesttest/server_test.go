@@ -0,0 +1,142 @@
+package mapquesttest_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/cking/mapquest"
+	"github.com/cking/mapquest/mapquesttest"
+)
+
+func TestServerCannedResponses(t *testing.T) {
+	srv := mapquesttest.NewServer()
+	defer srv.Close()
+	client := srv.Client("key")
+	ctx := context.Background()
+
+	geocode, err := client.Geocoding().AddressContext(ctx, &mapquest.GeocodeAddressRequest{Location: "Lancaster, PA"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := geocode.Results[0].Locations[0].LatLong; *got != mapquesttest.DefaultPoint {
+		t.Errorf("geocoded %v, want %v", got, mapquesttest.DefaultPoint)
+	}
+	if got := geocode.Results[0].ProvidedLocation.Location; got != "Lancaster, PA" {
+		t.Errorf("got provided location %q, want %q", got, "Lancaster, PA")
+	}
+
+	batch, err := client.Geocoding().Batch(ctx, &mapquest.GeocodeBatchRequest{Locations: []mapquest.Location{{Text: "York, PA"}, {Text: "Lancaster, PA"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(batch.Results) != 2 || batch.Results[1].Entry.ProvidedLocation.Location != "Lancaster, PA" {
+		t.Errorf("got batch results %+v", batch.Results)
+	}
+
+	search, err := client.Nominatim().SearchContext(ctx, &mapquest.NominatimSearchRequest{Query: "Unter den Linden"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(search.Results) != 1 || search.Results[0].DisplayName != "Unter den Linden" {
+		t.Errorf("got search results %+v", search.Results)
+	}
+
+	place, err := client.Nominatim().ReverseContext(ctx, &mapquest.NominatimReverseRequest{Latitude: 40, Longitude: -76})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if place.Latitude != mapquesttest.DefaultPoint.Latitude || place.Longitude != mapquesttest.DefaultPoint.Longitude {
+		t.Errorf("got place at %v,%v, want %v", place.Latitude, place.Longitude, mapquesttest.DefaultPoint)
+	}
+
+	for _, size := range []mapquest.StaticMapSize{{Width: 30, Height: 20}, {Width: 30, Height: 20, Retina: true}} {
+		res, err := client.StaticMap().MapResultContext(ctx, &mapquest.StaticMapRequest{Size: &size, Center: "Lancaster, PA"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		scale := 1
+		if size.Retina {
+			scale = 2
+		}
+		if res.Format != "png" || res.Width != scale*size.Width || res.Height != scale*size.Height {
+			t.Errorf("size %+v: got %s image of %dx%d", size, res.Format, res.Width, res.Height)
+		}
+	}
+}
+
+func TestServerEnqueue(t *testing.T) {
+	srv := mapquesttest.NewServer()
+	defer srv.Close()
+	client := srv.Client("key", mapquest.WithTimeout(50*time.Millisecond))
+	ctx := context.Background()
+	geocode := func() error {
+		_, err := client.Geocoding().AddressContext(ctx, &mapquest.GeocodeAddressRequest{Location: "Lancaster, PA"})
+		return err
+	}
+
+	srv.Enqueue(mapquesttest.PathGeocodeAddress,
+		mapquesttest.StatusResponse(http.StatusForbidden, "This key has exceeded its transaction quota."),
+		mapquesttest.StatusResponse(http.StatusBadRequest, "Illegal argument from request."),
+		mapquesttest.MalformedJSONResponse(),
+		mapquesttest.JSONResponse(nil).WithDelay(time.Second),
+	)
+
+	if err := geocode(); !errors.Is(err, mapquest.ErrQuotaExceeded) {
+		t.Errorf("got %v, want ErrQuotaExceeded", err)
+	}
+	if err := geocode(); !errors.Is(err, mapquest.ErrBadRequest) {
+		t.Errorf("got %v, want ErrBadRequest", err)
+	}
+	if err := geocode(); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("got %v, want a decoding error", err)
+	}
+	if err := geocode(); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got %v, want a timeout", err)
+	}
+
+	// the queue is drained, back to the canned response
+	if err := geocode(); err != nil {
+		t.Errorf("got %v, want the canned response", err)
+	}
+
+	requests := srv.RequestsTo(mapquesttest.PathGeocodeAddress)
+	if len(requests) != 5 {
+		t.Fatalf("got %d requests, want 5", len(requests))
+	}
+	for _, r := range requests {
+		if r.Method != "GET" || r.Query.Get("location") != "Lancaster, PA" || r.Query.Get("key") != "key" {
+			t.Errorf("got request %s %s?%s", r.Method, r.Path, r.Query.Encode())
+		}
+	}
+	if n := len(srv.RequestsTo(mapquesttest.PathNominatimSearch)); n != 0 {
+		t.Errorf("got %d requests to %s, want 0", n, mapquesttest.PathNominatimSearch)
+	}
+
+	srv.Reset()
+	if n := len(srv.Requests()); n != 0 {
+		t.Errorf("got %d requests after Reset, want 0", n)
+	}
+}
+
+func TestServerKey(t *testing.T) {
+	srv := mapquesttest.NewServer()
+	defer srv.Close()
+	srv.Key = "valid"
+	ctx := context.Background()
+
+	_, err := srv.Client("invalid").Geocoding().AddressContext(ctx, &mapquest.GeocodeAddressRequest{Location: "Lancaster, PA"})
+	if !errors.Is(err, mapquest.ErrInvalidKey) {
+		t.Errorf("geocoding: got %v, want ErrInvalidKey", err)
+	}
+	_, err = srv.Client("invalid").Nominatim().SearchContext(ctx, &mapquest.NominatimSearchRequest{Query: "Berlin"})
+	if !errors.Is(err, mapquest.ErrInvalidKey) {
+		t.Errorf("nominatim: got %v, want ErrInvalidKey", err)
+	}
+	if _, err := srv.Client("valid").Geocoding().AddressContext(ctx, &mapquest.GeocodeAddressRequest{Location: "Lancaster, PA"}); err != nil {
+		t.Errorf("valid key: %v", err)
+	}
+}
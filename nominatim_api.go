@@ -1,8 +1,10 @@
 package mapquest
 
 import (
+	"context"
 	"encoding/json"
-	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/google/go-querystring/query"
 )
@@ -25,7 +27,13 @@ func (api *NominatimAPI) SimpleSearch(query string, limit int) (*NominatimSearch
 	return api.Search(&NominatimSearchRequest{Query: query, Limit: limit})
 }
 
+// Search looks up the query given in req.
 func (api *NominatimAPI) Search(req *NominatimSearchRequest) (*NominatimSearchResponse, error) {
+	return api.SearchContext(context.Background(), req)
+}
+
+// SearchContext is like Search, but binds the request to ctx.
+func (api *NominatimAPI) SearchContext(ctx context.Context, req *NominatimSearchRequest) (*NominatimSearchResponse, error) {
 	q, err := query.Values(req)
 	if err != nil {
 		return nil, err
@@ -33,22 +41,12 @@ func (api *NominatimAPI) Search(req *NominatimSearchRequest) (*NominatimSearchRe
 
 	q.Set("key", api.c.key)
 	q.Set("format", "json")
-	u := apiURL(NominatimPrefix, NominatimVersion, "search.php")
+	u := api.c.apiURL(ServiceNominatim, NominatimVersion, "search.php")
 	u.RawQuery = q.Encode()
 
-	httpRequest, err := http.NewRequest("GET", u.String(), nil)
-	if err != nil {
-		return nil, err
-	}
-	httpRequest.Header.Set("User-Agent", UserAgent)
-	httpResponse, err := api.c.httpClient.Do(httpRequest)
-	if err != nil {
-		return nil, err
-	}
-	defer httpResponse.Body.Close()
-
-	var res *NominatimSearchResponse
-	if err := json.NewDecoder(httpResponse.Body).Decode(res); err != nil {
+	// the search endpoint answers with a plain array of entries
+	res := new(NominatimSearchResponse)
+	if err := api.c.getCachedJSON(ctx, ServiceNominatim, u, &res.Results); err != nil {
 		return nil, err
 	}
 
@@ -59,7 +57,13 @@ func (api *NominatimAPI) SimpleReverse(lat, long float64) (*NominatimSearchRespo
 	return api.Reverse(&NominatimReverseRequest{Latitude: lat, Longitude: long})
 }
 
+// Reverse looks up the place closest to the location given in req.
 func (api *NominatimAPI) Reverse(req *NominatimReverseRequest) (*NominatimSearchResponseEntry, error) {
+	return api.ReverseContext(context.Background(), req)
+}
+
+// ReverseContext is like Reverse, but binds the request to ctx.
+func (api *NominatimAPI) ReverseContext(ctx context.Context, req *NominatimReverseRequest) (*NominatimSearchResponseEntry, error) {
 	q, err := query.Values(req)
 	if err != nil {
 		return nil, err
@@ -67,22 +71,11 @@ func (api *NominatimAPI) Reverse(req *NominatimReverseRequest) (*NominatimSearch
 
 	q.Set("key", api.c.key)
 	q.Set("format", "json")
-	u := apiURL(NominatimPrefix, NominatimVersion, "reverse.php")
+	u := api.c.apiURL(ServiceNominatim, NominatimVersion, "reverse.php")
 	u.RawQuery = q.Encode()
 
-	httpRequest, err := http.NewRequest("GET", u.String(), nil)
-	if err != nil {
-		return nil, err
-	}
-	httpRequest.Header.Set("User-Agent", UserAgent)
-	httpResponse, err := api.c.httpClient.Do(httpRequest)
-	if err != nil {
-		return nil, err
-	}
-	defer httpResponse.Body.Close()
-
-	var res *NominatimSearchResponseEntry
-	if err := json.NewDecoder(httpResponse.Body).Decode(res); err != nil {
+	res := new(NominatimSearchResponseEntry)
+	if err := api.c.getCachedJSON(ctx, ServiceNominatim, u, res); err != nil {
 		return nil, err
 	}
 
@@ -131,18 +124,48 @@ type NominatimSearchResponseEntry struct {
 		StateDistrict string `json:"state_district,omitempty"`
 		Suburb        string `json:"suburb,omitempty"`
 	} `json:"address,omitempty"`
-	BoundingBox []float64 `json:"boundingbox,omitempty"`
-	Class       string    `json:"class,omitempty"`
-	DisplayName string    `json:"display_name,omitempty"`
-	Importance  float64   `json:"importance,omitempty"`
-	Latitude    float64   `json:"lat,string,omitempty"`
-	Longitude   float64   `json:"lon,string,omitempty"`
-	OSMId       string    `json:"osm_id,omitempty"`
-	OSMType     string    `json:"osm_type,omitempty"`
-	PlaceID     string    `json:"place_id,omitempty"`
-	Type        string    `json:"type,omitempty"`
-	License     string    `json:"licence,omitempty"` // typo in API
-	Icon        string    `json:"icon,omitempty"`
+	BoundingBox NominatimBoundingBox `json:"boundingbox,omitempty"`
+	Class       string               `json:"class,omitempty"`
+	DisplayName string               `json:"display_name,omitempty"`
+	Importance  float64              `json:"importance,omitempty"`
+	Latitude    float64              `json:"lat,string,omitempty"`
+	Longitude   float64              `json:"lon,string,omitempty"`
+	OSMId       string               `json:"osm_id,omitempty"`
+	OSMType     string               `json:"osm_type,omitempty"`
+	PlaceID     string               `json:"place_id,omitempty"`
+	Type        string               `json:"type,omitempty"`
+	License     string               `json:"licence,omitempty"` // typo in API
+	Icon        string               `json:"icon,omitempty"`
+
+	// Error is set by the reverse endpoint if no place could be found.
+	Error string `json:"error,omitempty"`
+}
+
+func (s *NominatimSearchResponseEntry) responseError() string {
+	return s.Error
+}
+
+// NominatimBoundingBox is the bounding box of a place as south, north,
+// west and east edge. Nominatim reports the edges as strings.
+type NominatimBoundingBox []float64
+
+func (s *NominatimBoundingBox) UnmarshalJSON(data []byte) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	box := make(NominatimBoundingBox, len(raw))
+	for i, r := range raw {
+		v := strings.Trim(string(r), `"`)
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return err
+		}
+		box[i] = f
+	}
+	*s = box
+	return nil
 }
 
 type NominatimReverseRequest struct {
@@ -0,0 +1,45 @@
+package mapquest_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/cking/mapquest"
+)
+
+func TestNominatimBoundingBox(t *testing.T) {
+	for _, data := range []string{
+		`["40.0","40.1","-76.4","-76.3"]`,
+		`[40.0,40.1,-76.4,-76.3]`,
+	} {
+		var box mapquest.NominatimBoundingBox
+		if err := json.Unmarshal([]byte(data), &box); err != nil {
+			t.Errorf("%s: %v", data, err)
+			continue
+		}
+		want := mapquest.NominatimBoundingBox{40.0, 40.1, -76.4, -76.3}
+		if len(box) != len(want) {
+			t.Errorf("%s: got %v, want %v", data, box, want)
+			continue
+		}
+		for i := range want {
+			if box[i] != want[i] {
+				t.Errorf("%s: got %v, want %v", data, box, want)
+				break
+			}
+		}
+	}
+
+	var entry mapquest.NominatimSearchResponseEntry
+	if err := json.Unmarshal([]byte(`{"boundingbox":["40.0","40.1","-76.4","-76.3"],"lat":"40.05","lon":"-76.35"}`), &entry); err != nil {
+		t.Fatal(err)
+	}
+	if len(entry.BoundingBox) != 4 || entry.BoundingBox[3] != -76.3 {
+		t.Errorf("got bounding box %v", entry.BoundingBox)
+	}
+
+	var box mapquest.NominatimBoundingBox
+	if err := json.Unmarshal([]byte(`["40.0","north"]`), &box); err == nil {
+		t.Errorf("decoded an invalid edge as %v", box)
+	}
+}
@@ -0,0 +1,73 @@
+package mapquest
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Service identifies one of the MapQuest services a Client talks to.
+type Service string
+
+const (
+	ServiceGeocoding Service = GeocodingPrefix
+	ServiceNominatim Service = NominatimPrefix
+	ServiceStaticMap Service = StaticMapPrefix
+	ServiceRoute     Service = RoutePrefix
+	ServiceElevation Service = ElevationPrefix
+	ServiceIncidents Service = IncidentsPrefix
+)
+
+// Option configures a Client. Pass options to NewClient.
+type Option func(*Client)
+
+// WithBaseURL sets the base URL of all services, e.g. to point the client
+// at a local stand-in or at the licensed https://www.mapquestapi.com
+// endpoints. The service path, e.g. /geocoding/v1/address, is appended to
+// the path of base. A nil base is ignored.
+func WithBaseURL(base *url.URL) Option {
+	return func(c *Client) {
+		if base == nil {
+			return
+		}
+		c.baseURL = base
+	}
+}
+
+// WithServiceURL sets the base URL of a single service, overriding the
+// one given by WithBaseURL. A nil base is ignored.
+func WithServiceURL(service Service, base *url.URL) Option {
+	return func(c *Client) {
+		if base == nil {
+			return
+		}
+		if c.serviceURLs == nil {
+			c.serviceURLs = make(map[Service]*url.URL)
+		}
+		c.serviceURLs[service] = base
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+// It defaults to UserAgent.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithHTTPClient sets the http.Client used for invoking the MapQuest API.
+// See SetHTTPClient.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *Client) {
+		c.SetHTTPClient(client)
+	}
+}
+
+// WithTimeout sets a default timeout for every request whose context does
+// not carry a deadline already. A zero timeout disables the default.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
@@ -0,0 +1,45 @@
+package mapquest_test
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/cking/mapquest"
+	"github.com/cking/mapquest/mapquesttest"
+)
+
+func TestWithServiceURL(t *testing.T) {
+	srv := mapquesttest.NewServer()
+	defer srv.Close()
+	nominatim := mapquesttest.NewServer()
+	defer nominatim.Close()
+	nominatimURL, _ := url.Parse(nominatim.URL)
+	client := srv.Client("key", mapquest.WithServiceURL(mapquest.ServiceNominatim, nominatimURL))
+	ctx := context.Background()
+
+	if _, err := client.Geocoding().AddressContext(ctx, &mapquest.GeocodeAddressRequest{Location: "Lancaster, PA"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Nominatim().SearchContext(ctx, &mapquest.NominatimSearchRequest{Query: "Berlin"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if n, m := len(srv.RequestsTo(mapquesttest.PathGeocodeAddress)), len(nominatim.RequestsTo(mapquesttest.PathGeocodeAddress)); n != 1 || m != 0 {
+		t.Errorf("geocoding: got %d requests to the base URL and %d to the nominatim URL, want 1 and 0", n, m)
+	}
+	if n, m := len(srv.RequestsTo(mapquesttest.PathNominatimSearch)), len(nominatim.RequestsTo(mapquesttest.PathNominatimSearch)); n != 0 || m != 1 {
+		t.Errorf("nominatim: got %d requests to the base URL and %d to the nominatim URL, want 0 and 1", n, m)
+	}
+}
+
+func TestWithNilURL(t *testing.T) {
+	client := mapquest.NewClient("key", mapquest.WithBaseURL(nil), mapquest.WithServiceURL(mapquest.ServiceStaticMap, nil))
+	u, err := client.StaticMap().URL(&mapquest.StaticMapRequest{Center: "Lancaster, PA"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Scheme != "https" || u.Host != mapquest.Host {
+		t.Errorf("got %s, want the default base URL", u)
+	}
+}
@@ -0,0 +1,112 @@
+package mapquest
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrRateLimited is returned by requests that would exceed the client-side
+// rate limit of a service configured with RateLimit.FailFast.
+var ErrRateLimited = errors.New("rate limited")
+
+// RateLimit configures the client-side throttling of a service.
+type RateLimit struct {
+	// Rate is the sustained number of requests per second.
+	Rate float64
+	// Burst is the number of requests that may be issued at once.
+	// It defaults to 1.
+	Burst int
+	// FailFast makes requests fail with ErrRateLimited instead of
+	// blocking until the limit allows them.
+	FailFast bool
+}
+
+// WithRateLimit throttles all requests of the client to service.
+func WithRateLimit(service Service, limit RateLimit) Option {
+	return WithRateLimiter(service, NewRateLimiter(limit))
+}
+
+// WithRateLimiter throttles all requests of the client to service using
+// limiter. Share a limiter between clients to enforce a common limit,
+// e.g. for clients using the same key.
+func WithRateLimiter(service Service, limiter *RateLimiter) Option {
+	return func(c *Client) {
+		if c.limiters == nil {
+			c.limiters = make(map[Service]*RateLimiter)
+		}
+		c.limiters[service] = limiter
+	}
+}
+
+// RateLimiter is a token bucket limiting the rate of requests. It is safe
+// for concurrent use.
+type RateLimiter struct {
+	limit RateLimit
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter creates a RateLimiter with a full bucket.
+func NewRateLimiter(limit RateLimit) *RateLimiter {
+	if limit.Burst < 1 {
+		limit.Burst = 1
+	}
+	return &RateLimiter{
+		limit:  limit,
+		tokens: float64(limit.Burst),
+		last:   time.Now(),
+	}
+}
+
+// refill adds the tokens accumulated since the last call. The caller must
+// hold l.mu.
+func (l *RateLimiter) refill(now time.Time) {
+	l.tokens += now.Sub(l.last).Seconds() * l.limit.Rate
+	if max := float64(l.limit.Burst); l.tokens > max {
+		l.tokens = max
+	}
+	l.last = now
+}
+
+// Wait takes a token from the bucket, blocking until one is available or
+// ctx is done. With FailFast set, it returns ErrRateLimited instead of
+// blocking.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	if l == nil || l.limit.Rate <= 0 {
+		return ctx.Err()
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	l.refill(now)
+	if l.tokens >= 1 {
+		l.tokens--
+		l.mu.Unlock()
+		return nil
+	}
+	if l.limit.FailFast {
+		l.mu.Unlock()
+		return ErrRateLimited
+	}
+
+	// reserve a token in advance, so concurrent waiters queue up
+	delay := time.Duration((1 - l.tokens) / l.limit.Rate * float64(time.Second))
+	if deadline, ok := ctx.Deadline(); ok && deadline.Before(now.Add(delay)) {
+		l.mu.Unlock()
+		return context.DeadlineExceeded
+	}
+	l.tokens--
+	l.mu.Unlock()
+
+	if err := sleep(ctx, delay); err != nil {
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package mapquest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cking/mapquest"
+	"github.com/cking/mapquest/mapquesttest"
+)
+
+func geocode(ctx context.Context, client *mapquest.Client) error {
+	_, err := client.Geocoding().AddressContext(ctx, &mapquest.GeocodeAddressRequest{Location: "Lancaster, PA"})
+	return err
+}
+
+func TestRateLimitFailFast(t *testing.T) {
+	srv := mapquesttest.NewServer()
+	defer srv.Close()
+	client := srv.Client("key", mapquest.WithRateLimit(mapquest.ServiceGeocoding, mapquest.RateLimit{Rate: 0.1, Burst: 3, FailFast: true}))
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if err := geocode(ctx, client); err != nil {
+			t.Fatalf("request %d of the burst: %v", i+1, err)
+		}
+	}
+	if err := geocode(ctx, client); !errors.Is(err, mapquest.ErrRateLimited) {
+		t.Errorf("got %v, want ErrRateLimited", err)
+	}
+	if n := len(srv.Requests()); n != 3 {
+		t.Errorf("got %d requests, want 3", n)
+	}
+
+	// other services are not limited
+	if _, err := client.Nominatim().SearchContext(ctx, &mapquest.NominatimSearchRequest{Query: "Berlin"}); err != nil {
+		t.Errorf("nominatim: %v", err)
+	}
+}
+
+func TestRateLimitDeadline(t *testing.T) {
+	srv := mapquesttest.NewServer()
+	defer srv.Close()
+	client := srv.Client("key", mapquest.WithRateLimit(mapquest.ServiceGeocoding, mapquest.RateLimit{Rate: 0.1}))
+
+	if err := geocode(context.Background(), client); err != nil {
+		t.Fatal(err)
+	}
+
+	// the next token is ten seconds away, so the request fails right away
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	start := time.Now()
+	if err := geocode(ctx, client); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got %v, want DeadlineExceeded", err)
+	}
+	if d := time.Since(start); d > 500*time.Millisecond {
+		t.Errorf("failed after %v, want right away", d)
+	}
+	if n := len(srv.Requests()); n != 1 {
+		t.Errorf("got %d requests, want 1", n)
+	}
+}
+
+func TestRateLimiterShared(t *testing.T) {
+	srv := mapquesttest.NewServer()
+	defer srv.Close()
+	limiter := mapquest.NewRateLimiter(mapquest.RateLimit{Rate: 0.1, Burst: 2, FailFast: true})
+	a := srv.Client("key", mapquest.WithRateLimiter(mapquest.ServiceGeocoding, limiter), mapquest.WithRateLimiter(mapquest.ServiceNominatim, limiter))
+	b := srv.Client("key", mapquest.WithRateLimiter(mapquest.ServiceGeocoding, limiter))
+	ctx := context.Background()
+
+	if err := geocode(ctx, a); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.Nominatim().SearchContext(ctx, &mapquest.NominatimSearchRequest{Query: "Berlin"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := geocode(ctx, b); !errors.Is(err, mapquest.ErrRateLimited) {
+		t.Errorf("got %v, want ErrRateLimited once the shared burst is used up", err)
+	}
+}
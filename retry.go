@@ -0,0 +1,143 @@
+package mapquest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy describes if and when failed requests are retried.
+// Pass it to NewClient via WithRetryPolicy. Without a policy, every
+// request is attempted exactly once.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts per request,
+	// including the first one.
+	MaxAttempts int
+	// MinBackoff is the delay before the first retry. It doubles with
+	// every further attempt.
+	MinBackoff time.Duration
+	// MaxBackoff caps the delay between two attempts.
+	MaxBackoff time.Duration
+	// Jitter is the fraction, between 0 and 1, by which a delay is
+	// randomly shortened to spread out retries of concurrent requests.
+	Jitter float64
+	// RetryableStatus lists the HTTP status codes worth retrying.
+	RetryableStatus []int
+	// RetryableError reports whether a transport error is worth retrying.
+	// If nil, timeouts and dropped connections are retried.
+	RetryableError func(error) bool
+}
+
+// DefaultRetryPolicy retries transient failures up to three times.
+var DefaultRetryPolicy = &RetryPolicy{
+	MaxAttempts: 4,
+	MinBackoff:  500 * time.Millisecond,
+	MaxBackoff:  30 * time.Second,
+	Jitter:      0.2,
+	RetryableStatus: []int{
+		http.StatusTooManyRequests,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout,
+	},
+}
+
+// WithRetryPolicy sets the policy for retrying failed requests.
+func WithRetryPolicy(policy *RetryPolicy) Option {
+	return func(c *Client) {
+		c.retryPolicy = policy
+	}
+}
+
+func (p *RetryPolicy) attempts() int {
+	if p == nil || p.MaxAttempts < 1 {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+func (p *RetryPolicy) retryStatus(status int) bool {
+	for _, s := range p.RetryableStatus {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *RetryPolicy) retryError(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if p.RetryableError != nil {
+		return p.RetryableError(err)
+	}
+	return isTransientError(err)
+}
+
+// backoff returns the delay before the given retry, counting from 1.
+func (p *RetryPolicy) backoff(retry int) time.Duration {
+	d := p.MinBackoff
+	for i := 1; i < retry && (p.MaxBackoff <= 0 || d < p.MaxBackoff); i++ {
+		d *= 2
+	}
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	if p.Jitter > 0 {
+		d -= time.Duration(rand.Float64() * p.Jitter * float64(d))
+	}
+	return d
+}
+
+func isTransientError(err error) bool {
+	if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr)
+}
+
+// retryAfter parses the Retry-After header of httpResponse, which is
+// either a number of seconds or an HTTP date.
+func retryAfter(httpResponse *http.Response) (time.Duration, bool) {
+	v := httpResponse.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
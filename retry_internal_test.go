@@ -0,0 +1,84 @@
+package mapquest
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	p := &RetryPolicy{MinBackoff: time.Second, MaxBackoff: 5 * time.Second}
+	for i, want := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second} {
+		retry := i + 1
+		if got := p.backoff(retry); got != want {
+			t.Errorf("backoff(%d) = %v, want %v", retry, got, want)
+		}
+	}
+
+	p.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if got := p.backoff(2); got <= time.Second || got > 2*time.Second {
+			t.Fatalf("jittered backoff(2) = %v, want within (1s, 2s]", got)
+		}
+	}
+
+	var none *RetryPolicy
+	if n := none.attempts(); n != 1 {
+		t.Errorf("nil policy: got %d attempts, want 1", n)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		header string
+		min    time.Duration
+		max    time.Duration
+		ok     bool
+	}{
+		{"", 0, 0, false},
+		{"120", 120 * time.Second, 120 * time.Second, true},
+		{"0", 0, 0, true},
+		{"-1", 0, 0, false},
+		{"soon", 0, 0, false},
+		{time.Now().Add(time.Minute).UTC().Format(http.TimeFormat), 58 * time.Second, time.Minute, true},
+		{time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat), 0, 0, true},
+	}
+	for _, tt := range tests {
+		res := &http.Response{Header: http.Header{}}
+		if tt.header != "" {
+			res.Header.Set("Retry-After", tt.header)
+		}
+		got, ok := retryAfter(res)
+		if ok != tt.ok || got < tt.min || got > tt.max {
+			t.Errorf("Retry-After %q: got %v, %v, want [%v, %v], %v", tt.header, got, ok, tt.min, tt.max, tt.ok)
+		}
+	}
+}
+
+func TestRetryPolicyRetryable(t *testing.T) {
+	p := DefaultRetryPolicy
+	for status, want := range map[int]bool{
+		http.StatusTooManyRequests:     true,
+		http.StatusBadGateway:          true,
+		http.StatusServiceUnavailable:  true,
+		http.StatusGatewayTimeout:      true,
+		http.StatusBadRequest:          false,
+		http.StatusForbidden:           false,
+		http.StatusInternalServerError: false,
+	} {
+		if got := p.retryStatus(status); got != want {
+			t.Errorf("retryStatus(%d) = %v, want %v", status, got, want)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if !p.retryError(ctx, io.ErrUnexpectedEOF) {
+		t.Error("dropped connection not retried")
+	}
+	cancel()
+	if p.retryError(ctx, io.ErrUnexpectedEOF) {
+		t.Error("retried after the context was done")
+	}
+}
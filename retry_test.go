@@ -0,0 +1,106 @@
+package mapquest_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/cking/mapquest"
+	"github.com/cking/mapquest/mapquesttest"
+)
+
+// retryPolicy retries 503 responses, with a backoff long enough that tests
+// only pass if it is not waited for.
+var retryPolicy = &mapquest.RetryPolicy{
+	MaxAttempts:     3,
+	MinBackoff:      time.Hour,
+	RetryableStatus: []int{http.StatusServiceUnavailable},
+}
+
+func TestRetryAfterUnavailable(t *testing.T) {
+	srv := mapquesttest.NewServer()
+	defer srv.Close()
+	client := srv.Client("key", mapquest.WithRetryPolicy(retryPolicy), mapquest.WithTimeout(5*time.Second))
+
+	unavailable := mapquesttest.StatusResponse(http.StatusServiceUnavailable, "Service unavailable")
+	unavailable.Header.Set("Retry-After", "0")
+	srv.Enqueue(mapquesttest.PathGeocodeAddress, unavailable)
+
+	res, err := client.Geocoding().AddressContext(context.Background(), &mapquest.GeocodeAddressRequest{Location: "Lancaster, PA"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := res.Results[0].Locations[0].LatLong; *got != mapquesttest.DefaultPoint {
+		t.Errorf("geocoded %v, want %v", got, mapquesttest.DefaultPoint)
+	}
+	if n := len(srv.Requests()); n != 2 {
+		t.Errorf("got %d requests, want 2", n)
+	}
+}
+
+func TestRetryNotRetryable(t *testing.T) {
+	srv := mapquesttest.NewServer()
+	defer srv.Close()
+	client := srv.Client("key", mapquest.WithRetryPolicy(retryPolicy))
+	srv.Enqueue(mapquesttest.PathGeocodeAddress, mapquesttest.StatusResponse(http.StatusBadRequest, "Illegal argument from request."))
+
+	_, err := client.Geocoding().AddressContext(context.Background(), &mapquest.GeocodeAddressRequest{Location: "Lancaster, PA"})
+	if !errors.Is(err, mapquest.ErrBadRequest) {
+		t.Errorf("got %v, want ErrBadRequest", err)
+	}
+	if n := len(srv.Requests()); n != 1 {
+		t.Errorf("got %d requests, want 1", n)
+	}
+}
+
+func TestRetryContextDone(t *testing.T) {
+	srv := mapquesttest.NewServer()
+	defer srv.Close()
+	client := srv.Client("key", mapquest.WithRetryPolicy(retryPolicy))
+	srv.Enqueue(mapquesttest.PathGeocodeAddress, mapquesttest.StatusResponse(http.StatusServiceUnavailable, "Service unavailable"))
+
+	// the backoff exceeds the deadline, so the request gives up
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := client.Geocoding().AddressContext(ctx, &mapquest.GeocodeAddressRequest{Location: "Lancaster, PA"})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got %v, want DeadlineExceeded", err)
+	}
+	if n := len(srv.Requests()); n != 1 {
+		t.Errorf("got %d requests, want 1", n)
+	}
+}
+
+func TestRetryPostBody(t *testing.T) {
+	srv := mapquesttest.NewServer()
+	defer srv.Close()
+	client := srv.Client("key", mapquest.WithRetryPolicy(retryPolicy))
+
+	unavailable := mapquesttest.StatusResponse(http.StatusServiceUnavailable, "Service unavailable")
+	unavailable.Header.Set("Retry-After", "0")
+	srv.Enqueue(mapquesttest.PathGeocodeBatch, unavailable, unavailable)
+
+	res, err := client.Geocoding().Batch(context.Background(), &mapquest.GeocodeBatchRequest{Locations: []mapquest.Location{{Text: "York, PA"}, {Text: "Lancaster, PA"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Results) != 2 {
+		t.Errorf("got %d results, want 2", len(res.Results))
+	}
+
+	requests := srv.RequestsTo(mapquesttest.PathGeocodeBatch)
+	if len(requests) != 3 {
+		t.Fatalf("got %d requests, want 3", len(requests))
+	}
+	if len(requests[0].Body) == 0 {
+		t.Fatal("got an empty body")
+	}
+	for i, r := range requests[1:] {
+		if r.Method != "POST" || !bytes.Equal(r.Body, requests[0].Body) {
+			t.Errorf("retry %d: got %s with body %s, want POST with body %s", i+1, r.Method, r.Body, requests[0].Body)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package mapquest
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+const (
+	RoutePrefix  = "directions"
+	RouteVersion = "v2"
+)
+
+// RouteAPI enables users to request routes via the MapQuest API.
+// See https://developer.mapquest.com/documentation/open/directions-api/ for details.
+type RouteAPI struct {
+	c *Client
+}
+
+// SimpleRoute calculates the fastest route from one address to another.
+func (api *RouteAPI) SimpleRoute(from, to string) (*RouteResponse, error) {
+	return api.Route(&RouteRequest{Locations: []Location{{Text: from}, {Text: to}}})
+}
+
+// Route calculates the route along the locations given in req.
+func (api *RouteAPI) Route(req *RouteRequest) (*RouteResponse, error) {
+	return api.RouteContext(context.Background(), req)
+}
+
+// RouteContext is like Route, but binds the request to ctx.
+func (api *RouteAPI) RouteContext(ctx context.Context, req *RouteRequest) (*RouteResponse, error) {
+	res := new(RouteResponse)
+	if err := api.c.postJSON(ctx, ServiceRoute, api.url("route"), req.body(), res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// OptimizedRoute calculates the route from the start to the end of req,
+// visiting its waypoints in the order that minimizes the total time or
+// distance.
+func (api *RouteAPI) OptimizedRoute(req *OptimizedRouteRequest) (*OptimizedRouteResponse, error) {
+	return api.OptimizedRouteContext(context.Background(), req)
+}
+
+// OptimizedRouteContext is like OptimizedRoute, but binds the request to ctx.
+func (api *RouteAPI) OptimizedRouteContext(ctx context.Context, req *OptimizedRouteRequest) (*OptimizedRouteResponse, error) {
+	body := &routeBody{Options: &req.Options}
+	body.Locations = append(body.Locations, req.Start)
+	body.Locations = append(body.Locations, req.Waypoints...)
+	body.Locations = append(body.Locations, req.End)
+
+	res := new(OptimizedRouteResponse)
+	if err := api.c.postJSON(ctx, ServiceRoute, api.url("optimizedroute"), body, &res.RouteResponse); err != nil {
+		return nil, err
+	}
+
+	// the location sequence refers to the posted locations, including
+	// start and end
+	if res.Route != nil {
+		for _, i := range res.Route.LocationSequence {
+			if i > 0 && i <= len(req.Waypoints) {
+				res.Order = append(res.Order, i-1)
+			}
+		}
+	}
+	if len(res.Order) != len(req.Waypoints) {
+		return nil, fmt.Errorf("mapquest: optimized route visits %d of %d waypoints", len(res.Order), len(req.Waypoints))
+	}
+
+	return res, nil
+}
+
+func (api *RouteAPI) url(endpoint string) *url.URL {
+	q := make(url.Values)
+	q.Set("key", api.c.key)
+	q.Set("outFormat", "json")
+	u := api.c.apiURL(ServiceRoute, RouteVersion, endpoint)
+	u.RawQuery = q.Encode()
+	return u
+}
+
+type RouteType string
+
+const (
+	RouteTypeFastest    RouteType = "fastest"
+	RouteTypeShortest   RouteType = "shortest"
+	RouteTypePedestrian RouteType = "pedestrian"
+	RouteTypeBicycle    RouteType = "bicycle"
+)
+
+type RouteAvoid string
+
+const (
+	RouteAvoidLimitedAccess   RouteAvoid = "Limited Access"
+	RouteAvoidTollRoad        RouteAvoid = "Toll Road"
+	RouteAvoidFerry           RouteAvoid = "Ferry"
+	RouteAvoidUnpaved         RouteAvoid = "Unpaved"
+	RouteAvoidSeasonalClosure RouteAvoid = "Approximate Seasonal Closure"
+	RouteAvoidBorderCrossing  RouteAvoid = "Country Border Crossing"
+	RouteAvoidBridge          RouteAvoid = "Bridge"
+	RouteAvoidTunnel          RouteAvoid = "Tunnel"
+)
+
+type RouteUnit string
+
+const (
+	RouteUnitMiles      RouteUnit = "m"
+	RouteUnitKilometers RouteUnit = "k"
+)
+
+// RouteOptions are the options shared by the routing requests.
+type RouteOptions struct {
+	RouteType RouteType    `json:"routeType,omitempty"`
+	Avoids    []RouteAvoid `json:"avoids,omitempty"`
+	Unit      RouteUnit    `json:"unit,omitempty"`
+	Locale    string       `json:"locale,omitempty"` // e.g. en_US
+	// NarrativeType is one of text (the default), html, microformat or none.
+	NarrativeType    string `json:"narrativeType,omitempty"`
+	DoReverseGeocode bool   `json:"doReverseGeocode,omitempty"`
+	ManeuverMaps     bool   `json:"manMaps"` // dont omit, omitempty works on false, default is true though
+}
+
+type RouteRequest struct {
+	Locations []Location
+	Options   RouteOptions
+}
+
+// routeBody is the JSON body posted to the routing endpoints.
+type routeBody struct {
+	Locations []Location    `json:"locations"`
+	Options   *RouteOptions `json:"options,omitempty"`
+}
+
+func (req *RouteRequest) body() *routeBody {
+	return &routeBody{Locations: req.Locations, Options: &req.Options}
+}
+
+// OptimizedRouteRequest requests the best route from Start to End, visiting
+// all Waypoints in any order.
+type OptimizedRouteRequest struct {
+	Start     Location
+	End       Location
+	Waypoints []Location
+	Options   RouteOptions
+}
+
+type OptimizedRouteResponse struct {
+	RouteResponse
+
+	// Order lists the indices of the waypoints of the request in the
+	// order they are visited.
+	Order []int
+}
+
+type RouteResponse struct {
+	Info  *ResponseInfo `json:"info,omitempty"`
+	Route *Route        `json:"route,omitempty"`
+}
+
+func (res *RouteResponse) responseInfo() *ResponseInfo {
+	return res.Info
+}
+
+type Route struct {
+	SessionID     string       `json:"sessionId,omitempty"`
+	Distance      float64      `json:"distance,omitempty"` // in RouteOptions.Unit
+	Time          int          `json:"time,omitempty"`     // in seconds
+	RealTime      int          `json:"realTime,omitempty"` // in seconds, including traffic
+	FormattedTime string       `json:"formattedTime,omitempty"`
+	FuelUsed      float64      `json:"fuelUsed,omitempty"`
+	HasTollRoad   bool         `json:"hasTollRoad,omitempty"`
+	HasFerry      bool         `json:"hasFerry,omitempty"`
+	HasHighway    bool         `json:"hasHighway,omitempty"`
+	HasUnpaved    bool         `json:"hasUnpaved,omitempty"`
+	BoundingBox   *BoundingBox `json:"boundingBox,omitempty"`
+	Legs          []*RouteLeg  `json:"legs,omitempty"`
+
+	// Locations are the geocoded locations of the request, in the order
+	// they are visited.
+	Locations        []*GeocodeAddressResponseLocationEntry `json:"locations,omitempty"`
+	LocationSequence []int                                  `json:"locationSequence,omitempty"`
+}
+
+// Narrative returns the narratives of all maneuvers along the route.
+func (r *Route) Narrative() []string {
+	var narrative []string
+	for _, leg := range r.Legs {
+		for _, m := range leg.Maneuvers {
+			narrative = append(narrative, m.Narrative)
+		}
+	}
+	return narrative
+}
+
+type RouteLeg struct {
+	Index         int              `json:"index"`
+	Distance      float64          `json:"distance,omitempty"`
+	Time          int              `json:"time,omitempty"`
+	FormattedTime string           `json:"formattedTime,omitempty"`
+	HasTollRoad   bool             `json:"hasTollRoad,omitempty"`
+	HasFerry      bool             `json:"hasFerry,omitempty"`
+	HasHighway    bool             `json:"hasHighway,omitempty"`
+	HasUnpaved    bool             `json:"hasUnpaved,omitempty"`
+	Maneuvers     []*RouteManeuver `json:"maneuvers,omitempty"`
+}
+
+type RouteManeuver struct {
+	Index         int       `json:"index"`
+	Narrative     string    `json:"narrative,omitempty"`
+	Distance      float64   `json:"distance,omitempty"`
+	Time          int       `json:"time,omitempty"`
+	FormattedTime string    `json:"formattedTime,omitempty"`
+	StartPoint    *GeoPoint `json:"startPoint,omitempty"`
+	Streets       []string  `json:"streets,omitempty"`
+	TurnType      int       `json:"turnType"`
+	Direction     int       `json:"direction"`
+	DirectionName string    `json:"directionName,omitempty"`
+	TransportMode string    `json:"transportMode,omitempty"`
+	IconURL       string    `json:"iconUrl,omitempty"`
+	MapURL        string    `json:"mapUrl,omitempty"`
+}
@@ -0,0 +1,65 @@
+package mapquest_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/cking/mapquest"
+	"github.com/cking/mapquest/mapquesttest"
+)
+
+const pathOptimizedRoute = "/directions/v2/optimizedroute"
+
+func TestOptimizedRouteOrder(t *testing.T) {
+	srv := mapquesttest.NewServer()
+	defer srv.Close()
+
+	// the posted locations are start, waypoints 0 to 2 and end; the route
+	// visits waypoint 2, then 0, then 1
+	legs := make([]*mapquest.RouteLeg, 4)
+	for i, from := range []string{"start", "waypoint 2", "waypoint 0", "waypoint 1"} {
+		legs[i] = &mapquest.RouteLeg{Index: i, Maneuvers: []*mapquest.RouteManeuver{{Narrative: "Leave " + from}}}
+	}
+	srv.Enqueue(pathOptimizedRoute, mapquesttest.JSONResponse(&mapquest.RouteResponse{
+		Info:  &mapquest.ResponseInfo{},
+		Route: &mapquest.Route{LocationSequence: []int{0, 3, 1, 2, 4}, Legs: legs},
+	}))
+
+	req := &mapquest.OptimizedRouteRequest{
+		Start: mapquest.Location{Text: "start"},
+		End:   mapquest.Location{Text: "end"},
+	}
+	for i := 0; i < 3; i++ {
+		req.Waypoints = append(req.Waypoints, mapquest.Location{Text: fmt.Sprintf("waypoint %d", i)})
+	}
+	res, err := srv.Client("key").Route().OptimizedRouteContext(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := fmt.Sprint(res.Order), "[2 0 1]"; got != want {
+		t.Errorf("got order %s, want %s", got, want)
+	}
+	// the legs after the first one start at the waypoints in Order
+	for i, w := range res.Order {
+		if got, want := res.Route.Legs[i+1].Maneuvers[0].Narrative, "Leave "+req.Waypoints[w].Text; got != want {
+			t.Errorf("leg %d: got %q, want %q", i+1, got, want)
+		}
+	}
+
+	requests := srv.RequestsTo(pathOptimizedRoute)
+	if len(requests) != 1 {
+		t.Fatalf("got %d requests, want 1", len(requests))
+	}
+	var body struct {
+		Locations []string `json:"locations"`
+	}
+	if err := json.Unmarshal(requests[0].Body, &body); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := fmt.Sprint(body.Locations), "[start waypoint 0 waypoint 1 waypoint 2 end]"; got != want {
+		t.Errorf("posted locations %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,258 @@
+package mapquest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+const (
+	// RouteMatrixOneToManyLimit is the maximum number of locations per
+	// one-to-many or many-to-one call.
+	RouteMatrixOneToManyLimit = 100
+	// RouteMatrixAllToAllLimit is the maximum number of locations per
+	// many-to-many call.
+	RouteMatrixAllToAllLimit = 25
+)
+
+// ErrNoRouteMatrixValue is the error of matrix cells the API returned no
+// distance or time for.
+var ErrNoRouteMatrixValue = errors.New("no route matrix value")
+
+// RouteMatrixAPI enables users to request distance and time matrices via
+// the MapQuest API.
+// See https://developer.mapquest.com/documentation/open/directions-api/route-matrix/post/ for details.
+type RouteMatrixAPI struct {
+	c *Client
+}
+
+// Matrix calculates the distances and times from every origin to every
+// destination given in req. A single origin or destination is requested
+// in one-to-many or many-to-one mode, anything else in many-to-many mode.
+// Requests exceeding the location limits of the API are split into tiles,
+// which are merged into one matrix.
+func (api *RouteMatrixAPI) Matrix(req *RouteMatrixRequest) (*RouteMatrix, error) {
+	return api.MatrixContext(context.Background(), req)
+}
+
+// MatrixContext is like Matrix, but binds the request to ctx.
+func (api *RouteMatrixAPI) MatrixContext(ctx context.Context, req *RouteMatrixRequest) (*RouteMatrix, error) {
+	origins, destinations := req.Origins, req.Destinations
+	matrix := &RouteMatrix{Cells: make([][]RouteMatrixCell, len(origins))}
+	for i := range matrix.Cells {
+		matrix.Cells[i] = make([]RouteMatrixCell, len(destinations))
+	}
+	if len(origins) == 0 || len(destinations) == 0 {
+		return matrix, nil
+	}
+
+	var (
+		oc, dc   int
+		allToAll bool
+	)
+	switch {
+	case len(origins) == 1:
+		oc, dc = 1, RouteMatrixOneToManyLimit-1
+	case len(destinations) == 1:
+		oc, dc = RouteMatrixOneToManyLimit-1, 1
+	default:
+		allToAll = true
+		oc = RouteMatrixAllToAllLimit / 2
+		if oc > len(origins) {
+			oc = len(origins)
+		}
+		dc = RouteMatrixAllToAllLimit - oc
+		if dc > len(destinations) {
+			dc = len(destinations)
+			oc = RouteMatrixAllToAllLimit - dc
+		}
+	}
+
+	for o := 0; o < len(origins); o += oc {
+		oe := o + oc
+		if oe > len(origins) {
+			oe = len(origins)
+		}
+		for d := 0; d < len(destinations); d += dc {
+			de := d + dc
+			if de > len(destinations) {
+				de = len(destinations)
+			}
+			if err := api.tile(ctx, req, matrix, o, oe, d, de, allToAll); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return matrix, nil
+}
+
+// tile requests the cells of origins [o, oe) to destinations [d, de) and
+// stores them in matrix.
+func (api *RouteMatrixAPI) tile(ctx context.Context, req *RouteMatrixRequest, matrix *RouteMatrix, o, oe, d, de int, allToAll bool) error {
+	no := oe - o
+	manyToOne := !allToAll && no > 1
+
+	body := &routeMatrixBody{Options: routeMatrixOptions{
+		RouteOptions: req.Options,
+		AllToAll:     allToAll,
+		ManyToOne:    manyToOne,
+	}}
+	body.Locations = append(body.Locations, req.Origins[o:oe]...)
+	if manyToOne {
+		// many-to-one expects the destination first
+		body.Locations = append(req.Destinations[d:de:de], body.Locations...)
+	} else {
+		body.Locations = append(body.Locations, req.Destinations[d:de]...)
+	}
+
+	res := new(routeMatrixResponse)
+	err := api.c.postJSON(ctx, ServiceRoute, api.c.Route().url("routematrix"), body, res)
+	if errors.Is(err, ErrRouteNotFound) {
+		// no route between the locations of the tile, flag its cells
+		for i := o; i < oe; i++ {
+			for j := d; j < de; j++ {
+				matrix.Cells[i][j].Err = err
+			}
+		}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// cell returns the value of location i to location j of the tile
+	cell := func(i, j int) RouteMatrixCell {
+		var c RouteMatrixCell
+		var dist *float64
+		var secs *int
+		if allToAll {
+			if i < len(res.allDistance) && j < len(res.allDistance[i]) {
+				dist = res.allDistance[i][j]
+			}
+			if i < len(res.allTime) && j < len(res.allTime[i]) {
+				secs = res.allTime[i][j]
+			}
+		} else {
+			// one-to-many and many-to-one only report the row of the
+			// first location
+			k := j
+			if manyToOne {
+				k = i
+			}
+			if k < len(res.distance) {
+				dist = res.distance[k]
+			}
+			if k < len(res.time) {
+				secs = res.time[k]
+			}
+		}
+		if dist == nil || secs == nil || *dist < 0 || *secs < 0 {
+			c.Err = ErrNoRouteMatrixValue
+			return c
+		}
+		c.Distance, c.Time = *dist, *secs
+		return c
+	}
+
+	for i := o; i < oe; i++ {
+		for j := d; j < de; j++ {
+			switch {
+			case allToAll:
+				matrix.Cells[i][j] = cell(i-o, no+j-d)
+			case manyToOne:
+				matrix.Cells[i][j] = cell(1+i-o, 0)
+			default:
+				matrix.Cells[i][j] = cell(0, 1+j-d)
+			}
+		}
+	}
+
+	return nil
+}
+
+type RouteMatrixRequest struct {
+	Origins      []Location
+	Destinations []Location
+	Options      RouteOptions
+}
+
+// RouteMatrix holds the distances and times between origins and
+// destinations. Cells is indexed by origin, then by destination.
+type RouteMatrix struct {
+	Cells [][]RouteMatrixCell
+}
+
+// Cell returns the cell from the origin at index o to the destination at
+// index d.
+func (m *RouteMatrix) Cell(o, d int) RouteMatrixCell {
+	return m.Cells[o][d]
+}
+
+type RouteMatrixCell struct {
+	Distance float64 // in RouteOptions.Unit
+	Time     int     // in seconds
+
+	// Err is set if the API did not return a value for the cell, or
+	// found no route for the tile of the cell.
+	Err error
+}
+
+type routeMatrixOptions struct {
+	RouteOptions
+	AllToAll  bool `json:"allToAll,omitempty"`
+	ManyToOne bool `json:"manyToOne,omitempty"`
+}
+
+// routeMatrixBody is the JSON body posted to the route matrix endpoint.
+type routeMatrixBody struct {
+	Locations []Location         `json:"locations"`
+	Options   routeMatrixOptions `json:"options"`
+}
+
+// routeMatrixResponse decodes distance and time as lists or as matrices,
+// depending on allToAll.
+type routeMatrixResponse struct {
+	Info *ResponseInfo `json:"info,omitempty"`
+
+	distance    []*float64
+	time        []*int
+	allDistance [][]*float64
+	allTime     [][]*int
+}
+
+func (res *routeMatrixResponse) responseInfo() *ResponseInfo {
+	return res.Info
+}
+
+func (res *routeMatrixResponse) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Info     *ResponseInfo   `json:"info"`
+		AllToAll bool            `json:"allToAll"`
+		Distance json.RawMessage `json:"distance"`
+		Time     json.RawMessage `json:"time"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	res.Info = raw.Info
+	if len(raw.Distance) == 0 || len(raw.Time) == 0 {
+		return nil
+	}
+
+	var err error
+	if raw.AllToAll {
+		if err = json.Unmarshal(raw.Distance, &res.allDistance); err == nil {
+			err = json.Unmarshal(raw.Time, &res.allTime)
+		}
+	} else {
+		if err = json.Unmarshal(raw.Distance, &res.distance); err == nil {
+			err = json.Unmarshal(raw.Time, &res.time)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("route matrix: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package mapquest_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/cking/mapquest"
+	"github.com/cking/mapquest/mapquesttest"
+)
+
+const pathRouteMatrix = "/directions/v2/routematrix"
+
+func matrixRequest(origins, destinations int) *mapquest.RouteMatrixRequest {
+	req := new(mapquest.RouteMatrixRequest)
+	for i := 0; i < origins; i++ {
+		req.Origins = append(req.Origins, mapquest.Location{Text: fmt.Sprintf("origin %d", i)})
+	}
+	for i := 0; i < destinations; i++ {
+		req.Destinations = append(req.Destinations, mapquest.Location{Text: fmt.Sprintf("destination %d", i)})
+	}
+	return req
+}
+
+func TestRouteMatrixRouteNotFound(t *testing.T) {
+	srv := mapquesttest.NewServer()
+	defer srv.Close()
+	srv.Enqueue(pathRouteMatrix, mapquesttest.JSONResponse(map[string]interface{}{
+		"info": &mapquest.ResponseInfo{StatusCode: 402, Messages: []string{"We are unable to route with the given locations."}},
+	}))
+
+	m, err := srv.Client("key").RouteMatrix().Matrix(matrixRequest(2, 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for o, row := range m.Cells {
+		for d, cell := range row {
+			if !errors.Is(cell.Err, mapquest.ErrRouteNotFound) {
+				t.Errorf("cell %d,%d: got error %v, want ErrRouteNotFound", o, d, cell.Err)
+			}
+		}
+	}
+}
+
+func TestRouteMatrixAbortsOnKeyErrors(t *testing.T) {
+	for _, res := range []*mapquesttest.Response{
+		mapquesttest.StatusResponse(http.StatusForbidden, "The AppKey submitted with this request is invalid."),
+		mapquesttest.StatusResponse(http.StatusForbidden, "This key has exceeded its transaction quota."),
+		mapquesttest.StatusResponse(http.StatusInternalServerError, "Internal error"),
+	} {
+		srv := mapquesttest.NewServer()
+		srv.Enqueue(pathRouteMatrix, res)
+
+		// 20 by 20 locations are split into four tiles
+		m, err := srv.Client("key").RouteMatrix().Matrix(matrixRequest(20, 20))
+		var apiErr *mapquest.APIError
+		if !errors.As(err, &apiErr) || m != nil {
+			t.Errorf("got matrix %v, error %v, want an APIError", m, err)
+		}
+		if n := len(srv.RequestsTo(pathRouteMatrix)); n != 1 {
+			t.Errorf("%v: got %d requests, want 1", err, n)
+		}
+		srv.Close()
+	}
+}
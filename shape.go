@@ -0,0 +1,122 @@
+package mapquest
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrInvalidShape is returned when decoding a malformed compressed shape
+// or polyline.
+var ErrInvalidShape = errors.New("invalid compressed shape")
+
+// ShapeFormat is the format of coordinate lists exchanged with the API.
+// See https://developer.mapquest.com/documentation/common/encode-decode/
+type ShapeFormat string
+
+const (
+	// ShapeFormatRaw is a plain list of coordinates.
+	ShapeFormatRaw ShapeFormat = "raw"
+	// ShapeFormatCmp is the compressed format with 5 decimal places.
+	ShapeFormatCmp ShapeFormat = "cmp"
+	// ShapeFormatCmp6 is the compressed format with 6 decimal places.
+	ShapeFormatCmp6 ShapeFormat = "cmp6"
+)
+
+// Precision returns the number of decimal places of a compressed format.
+func (f ShapeFormat) Precision() int {
+	if f == ShapeFormatCmp6 {
+		return 6
+	}
+	return 5
+}
+
+// EncodeCompressedShape encodes points in the MapQuest compressed shape
+// format with the given number of decimal places, e.g. 5 for cmp or 6
+// for cmp6.
+func EncodeCompressedShape(points []GeoPoint, precision int) string {
+	factor := math.Pow10(precision)
+	buf := make([]byte, 0, len(points)*8)
+
+	var lastLat, lastLng int64
+	for _, p := range points {
+		lat := int64(math.Round(p.Latitude * factor))
+		lng := int64(math.Round(p.Longitude * factor))
+		buf = appendShapeNumber(buf, lat-lastLat)
+		buf = appendShapeNumber(buf, lng-lastLng)
+		lastLat, lastLng = lat, lng
+	}
+
+	return string(buf)
+}
+
+// DecodeCompressedShape decodes a shape in the MapQuest compressed shape
+// format with the given number of decimal places.
+func DecodeCompressedShape(shape string, precision int) ([]GeoPoint, error) {
+	factor := math.Pow10(precision)
+	points := make([]GeoPoint, 0, len(shape)/8)
+
+	var lat, lng int64
+	for i := 0; i < len(shape); {
+		dLat, n, err := readShapeNumber(shape[i:])
+		if err != nil {
+			return nil, err
+		}
+		i += n
+		dLng, n, err := readShapeNumber(shape[i:])
+		if err != nil {
+			return nil, err
+		}
+		i += n
+
+		lat += dLat
+		lng += dLng
+		points = append(points, GeoPoint{Latitude: float64(lat) / factor, Longitude: float64(lng) / factor})
+	}
+
+	return points, nil
+}
+
+// EncodePolyline encodes points in the Google encoded polyline format.
+// It is the compressed shape format with 5 decimal places.
+func EncodePolyline(points []GeoPoint) string {
+	return EncodeCompressedShape(points, 5)
+}
+
+// DecodePolyline decodes a polyline in the Google encoded polyline format.
+func DecodePolyline(polyline string) ([]GeoPoint, error) {
+	return DecodeCompressedShape(polyline, 5)
+}
+
+func appendShapeNumber(buf []byte, n int64) []byte {
+	n <<= 1
+	if n < 0 {
+		n = ^n
+	}
+	for n >= 0x20 {
+		buf = append(buf, byte((0x20|(n&0x1f))+63))
+		n >>= 5
+	}
+	return append(buf, byte(n+63))
+}
+
+// readShapeNumber reads a number from the start of s and returns it along
+// with the number of bytes consumed.
+func readShapeNumber(s string) (int64, int, error) {
+	var n int64
+	var shift uint
+	for i := 0; i < len(s); i++ {
+		b := int64(s[i]) - 63
+		if b < 0 || b > 0x3f || shift > 60 {
+			return 0, 0, ErrInvalidShape
+		}
+		n |= (b & 0x1f) << shift
+		shift += 5
+		if b < 0x20 {
+			if n&1 != 0 {
+				return ^(n >> 1), i + 1, nil
+			}
+			return n >> 1, i + 1, nil
+		}
+	}
+	return 0, 0, ErrInvalidShape
+}
@@ -0,0 +1,87 @@
+package mapquest
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func randomPoints(rnd *rand.Rand, n int) []GeoPoint {
+	points := make([]GeoPoint, n)
+	for i := range points {
+		points[i] = GeoPoint{Latitude: rnd.Float64()*180 - 90, Longitude: rnd.Float64()*360 - 180}
+	}
+	return points
+}
+
+func TestShapeRoundTrip(t *testing.T) {
+	codecs := []struct {
+		name      string
+		precision int
+		encode    func([]GeoPoint) string
+		decode    func(string) ([]GeoPoint, error)
+	}{
+		{"cmp", ShapeFormatCmp.Precision(), func(p []GeoPoint) string { return EncodeCompressedShape(p, 5) }, func(s string) ([]GeoPoint, error) { return DecodeCompressedShape(s, 5) }},
+		{"cmp6", ShapeFormatCmp6.Precision(), func(p []GeoPoint) string { return EncodeCompressedShape(p, 6) }, func(s string) ([]GeoPoint, error) { return DecodeCompressedShape(s, 6) }},
+		{"polyline", 5, EncodePolyline, DecodePolyline},
+	}
+
+	rnd := rand.New(rand.NewSource(1))
+	for _, c := range codecs {
+		// rounding to the precision plus some slack for float arithmetic
+		tolerance := 0.5*math.Pow10(-c.precision) + 1e-9
+		for run := 0; run < 200; run++ {
+			points := randomPoints(rnd, rnd.Intn(50))
+			shape := c.encode(points)
+			decoded, err := c.decode(shape)
+			if err != nil {
+				t.Fatalf("%s: decoding %q: %v", c.name, shape, err)
+			}
+			if len(decoded) != len(points) {
+				t.Fatalf("%s: decoded %d points, want %d", c.name, len(decoded), len(points))
+			}
+			for i, p := range points {
+				if math.Abs(decoded[i].Latitude-p.Latitude) > tolerance || math.Abs(decoded[i].Longitude-p.Longitude) > tolerance {
+					t.Fatalf("%s: point %d decoded as %v, want %v within %g", c.name, i, decoded[i], p, tolerance)
+				}
+			}
+		}
+	}
+}
+
+func TestDecodePolylineReference(t *testing.T) {
+	// the example of the Google encoded polyline documentation
+	points, err := DecodePolyline("_p~iF~ps|U_ulLnnqC_mqNvxq`@")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []GeoPoint{{38.5, -120.2}, {40.7, -120.95}, {43.252, -126.453}}
+	if len(points) != len(want) {
+		t.Fatalf("got %v, want %v", points, want)
+	}
+	for i := range want {
+		if math.Abs(points[i].Latitude-want[i].Latitude) > 1e-9 || math.Abs(points[i].Longitude-want[i].Longitude) > 1e-9 {
+			t.Errorf("point %d: got %v, want %v", i, points[i], want[i])
+		}
+	}
+
+	if got := EncodePolyline(want); got != "_p~iF~ps|U_ulLnnqC_mqNvxq`@" {
+		t.Errorf("got %q, want %q", got, "_p~iF~ps|U_ulLnnqC_mqNvxq`@")
+	}
+}
+
+func TestDecodeCompressedShapeInvalid(t *testing.T) {
+	for _, shape := range []string{
+		"_",                    // unterminated number
+		"_p~iF",                // latitude without longitude
+		"_p~iF~ps|U_",          // unterminated second point
+		"_p~iF ~ps|U",          // character below the alphabet
+		"_p~iF\x7f",            // character above the alphabet
+		"~~~~~~~~~~~~~~~~~~~?", // number overflowing 64 bits
+	} {
+		if _, err := DecodeCompressedShape(shape, 5); !errors.Is(err, ErrInvalidShape) {
+			t.Errorf("DecodeCompressedShape(%q) returned %v, want ErrInvalidShape", shape, err)
+		}
+	}
+}
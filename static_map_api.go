@@ -1,11 +1,12 @@
 package mapquest
 
 import (
+	"context"
 	"fmt"
 	"image"
 	"io"
-	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 
 	// static map returns a raw gif, jpeg or png object
@@ -28,38 +29,140 @@ type StaticMapAPI struct {
 	c *Client
 }
 
+// Map fetches the static map described by req and decodes it.
 func (api *StaticMapAPI) Map(req *StaticMapRequest) (image.Image, error) {
-	reader, err := api.MapReader(req)
+	return api.MapContext(context.Background(), req)
+}
+
+// MapContext is like Map, but binds the request to ctx.
+func (api *StaticMapAPI) MapContext(ctx context.Context, req *StaticMapRequest) (image.Image, error) {
+	res, err := api.MapResultContext(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return res.Image, nil
+}
+
+// MapResult is like Map, but also returns the metadata of the image.
+func (api *StaticMapAPI) MapResult(req *StaticMapRequest) (*StaticMapResult, error) {
+	return api.MapResultContext(context.Background(), req)
+}
+
+// MapResultContext is like MapResult, but binds the request to ctx.
+func (api *StaticMapAPI) MapResultContext(ctx context.Context, req *StaticMapRequest) (*StaticMapResult, error) {
+	reader, err := api.MapReaderContext(ctx, req)
 	if err != nil {
 		return nil, err
 	}
 	defer reader.Close()
 
-	img, _, err := image.Decode(reader)
-	return img, err
+	return decodeStaticMap(reader)
 }
 
-func (api *StaticMapAPI) MapReader(req *StaticMapRequest) (io.ReadCloser, error) {
-	q, err := query.Values(req)
+// StaticMapResult is a decoded map image along with its metadata.
+type StaticMapResult struct {
+	Image image.Image
+	// Format is the format name reported by image.Decode, e.g. png.
+	Format string
+	// Size is the size of the encoded image in bytes.
+	Size   int64
+	Width  int
+	Height int
+}
+
+// countingReader counts the bytes read through it.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+func decodeStaticMap(r io.Reader) (*StaticMapResult, error) {
+	cr := &countingReader{r: r}
+	img, format, err := image.Decode(cr)
 	if err != nil {
 		return nil, err
 	}
+	// decoders may stop before the end of the data
+	if _, err := io.Copy(io.Discard, cr); err != nil {
+		return nil, err
+	}
 
-	q.Set("key", api.c.key)
-	u := apiURL(StaticMapPrefix, StaticMapVersion, "map")
-	u.RawQuery = q.Encode()
+	bounds := img.Bounds()
+	return &StaticMapResult{
+		Image:  img,
+		Format: format,
+		Size:   cr.n,
+		Width:  bounds.Dx(),
+		Height: bounds.Dy(),
+	}, nil
+}
+
+// MapReader fetches the static map described by req. The caller is
+// responsible for closing the returned reader.
+func (api *StaticMapAPI) MapReader(req *StaticMapRequest) (io.ReadCloser, error) {
+	return api.MapReaderContext(context.Background(), req)
+}
 
-	httpRequest, err := http.NewRequest("GET", u.String(), nil)
+// MapReaderContext is like MapReader, but binds the request to ctx.
+func (api *StaticMapAPI) MapReaderContext(ctx context.Context, req *StaticMapRequest) (io.ReadCloser, error) {
+	u, err := api.URL(req)
 	if err != nil {
 		return nil, err
 	}
-	httpRequest.Header.Set("User-Agent", UserAgent)
-	httpResponse, err := api.c.httpClient.Do(httpRequest)
+
+	return api.c.getImage(ctx, ServiceStaticMap, u)
+}
+
+// StaticMapURLOption changes how StaticMapAPI.URL handles the key.
+type StaticMapURLOption func(u *url.URL, q url.Values)
+
+// StaticMapURLWithoutKey leaves the key out of the URL.
+func StaticMapURLWithoutKey() StaticMapURLOption {
+	return func(u *url.URL, q url.Values) {
+		q.Del("key")
+		u.RawQuery = q.Encode()
+	}
+}
+
+// StaticMapURLKeyPlaceholder replaces the key with placeholder, e.g. to
+// fill it in later in a template. The placeholder is not escaped.
+func StaticMapURLKeyPlaceholder(placeholder string) StaticMapURLOption {
+	return func(u *url.URL, q url.Values) {
+		q.Del("key")
+		u.RawQuery = q.Encode() + "&key=" + placeholder
+	}
+}
+
+// URL returns the URL of the static map described by req without fetching
+// it, e.g. to reference it from HTML. The URL includes the key unless
+// options say otherwise.
+func (api *StaticMapAPI) URL(req *StaticMapRequest, options ...StaticMapURLOption) (*url.URL, error) {
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+
+	q, err := query.Values(req)
 	if err != nil {
 		return nil, err
 	}
+	if req.RotueArc {
+		q.Set("routeArc", "true")
+	}
+
+	q.Set("key", api.c.key)
+	u := api.c.apiURL(ServiceStaticMap, StaticMapVersion, "map")
+	u.RawQuery = q.Encode()
+	for _, option := range options {
+		option(u, q)
+	}
 
-	return httpResponse.Body, nil
+	return u, nil
 }
 
 type StaticMapSize struct {
@@ -230,13 +333,16 @@ type StaticMapColor struct {
 }
 
 func (s *StaticMapColor) EncodeValues(key string, v *url.Values) error {
+	v.Set(key, s.hex())
+	return nil
+}
+
+// hex returns the color as rrggbb, or rrggbbaa if it has an alpha value.
+func (s *StaticMapColor) hex() string {
 	if s.A == 0 {
-		v.Set(key, fmt.Sprint("%d,%d,%d", s.R, s.G, s.B))
-	} else {
-		v.Set(key, fmt.Sprint("%d,%d,%d,%d", s.R, s.G, s.B, s.A))
+		return fmt.Sprintf("%02x%02x%02x", s.R, s.G, s.B)
 	}
-
-	return nil
+	return fmt.Sprintf("%02x%02x%02x%02x", s.R, s.G, s.B, s.A)
 }
 
 func StaticMapColorHex(h int) *StaticMapColor {
@@ -255,7 +361,144 @@ func StaticMapColorHexAlpha(h int) *StaticMapColor {
 	return c
 }
 
-type StaticMapShape struct {
+// staticMapShapeCompressThreshold is the length of the raw coordinates of
+// all shapes from which on they are sent compressed, keeping the URL
+// short.
+const staticMapShapeCompressThreshold = 1024
+
+// StaticMapShape is a shape drawn on a static map. It is implemented by
+// StaticMapPolyline, StaticMapPolygon and StaticMapCircle.
+type StaticMapShape interface {
+	// shapeOptions returns the style options of the shape, e.g. border:ff0000.
+	shapeOptions() []string
+	// shapePoints returns the coordinates of the shape.
+	shapePoints() []GeoPoint
+}
+
+// StaticMapPolyline is an open path, e.g. a track.
+type StaticMapPolyline struct {
+	Points []GeoPoint
+	Color  *StaticMapColor
+	Width  int
+}
+
+func (s *StaticMapPolyline) shapeOptions() []string {
+	var opts []string
+	if s.Color != nil {
+		opts = append(opts, "border:"+s.Color.hex())
+	}
+	if s.Width > 0 {
+		opts = append(opts, "width:"+strconv.Itoa(s.Width))
+	}
+	return opts
+}
+
+func (s *StaticMapPolyline) shapePoints() []GeoPoint {
+	return s.Points
+}
+
+// StaticMapPolygon is a closed area, e.g. a service area.
+type StaticMapPolygon struct {
+	Points []GeoPoint
+	Fill   *StaticMapColor
+	Border *StaticMapColor
+	Width  int
+}
+
+func (s *StaticMapPolygon) shapeOptions() []string {
+	var opts []string
+	if s.Border != nil {
+		opts = append(opts, "border:"+s.Border.hex())
+	}
+	// a fill closes the shape, transparent if the caller did not pick one
+	fill := "00000000"
+	if s.Fill != nil {
+		fill = s.Fill.hex()
+	}
+	opts = append(opts, "fill:"+fill)
+	if s.Width > 0 {
+		opts = append(opts, "width:"+strconv.Itoa(s.Width))
+	}
+	return opts
+}
+
+func (s *StaticMapPolygon) shapePoints() []GeoPoint {
+	return s.Points
+}
+
+type StaticMapRadiusUnit string
+
+const (
+	StaticMapRadiusKilometers StaticMapRadiusUnit = "km"
+	StaticMapRadiusMiles      StaticMapRadiusUnit = "mi"
+)
+
+// StaticMapCircle is a circle of Radius around Center.
+type StaticMapCircle struct {
+	Center GeoPoint
+	Radius float64
+	Unit   StaticMapRadiusUnit // defaults to kilometers
+	Fill   *StaticMapColor
+	Border *StaticMapColor
+	Width  int
+}
+
+func (s *StaticMapCircle) shapeOptions() []string {
+	unit := s.Unit
+	if unit == "" {
+		unit = StaticMapRadiusKilometers
+	}
+
+	opts := []string{"radius:" + strconv.FormatFloat(s.Radius, 'f', -1, 64) + string(unit)}
+	if s.Border != nil {
+		opts = append(opts, "border:"+s.Border.hex())
+	}
+	if s.Fill != nil {
+		opts = append(opts, "fill:"+s.Fill.hex())
+	}
+	if s.Width > 0 {
+		opts = append(opts, "width:"+strconv.Itoa(s.Width))
+	}
+	return opts
+}
+
+func (s *StaticMapCircle) shapePoints() []GeoPoint {
+	return []GeoPoint{s.Center}
+}
+
+// StaticMapShapes encodes to one shape parameter per shape. If the
+// coordinates get too long, all shapes are compressed. Compressed paths
+// are given last, after a cmp6|enc: prefix marking the rest of the value
+// as the path, as the compressed alphabet includes the | separator.
+type StaticMapShapes []StaticMapShape
+
+func (s StaticMapShapes) EncodeValues(key string, v *url.Values) error {
+	raw := make([]string, len(s))
+	length := 0
+	for i, shape := range s {
+		points := shape.shapePoints()
+		coords := make([]string, len(points))
+		for j, p := range points {
+			coords[j] = strconv.FormatFloat(p.Latitude, 'f', -1, 64) + "," + strconv.FormatFloat(p.Longitude, 'f', -1, 64)
+		}
+		raw[i] = strings.Join(coords, "|")
+		length += len(raw[i])
+	}
+
+	compress := length > staticMapShapeCompressThreshold
+
+	v.Del(key)
+	for i, shape := range s {
+		parts := shape.shapeOptions()
+		if compress {
+			parts = append(parts, string(ShapeFormatCmp6), "enc:"+EncodeCompressedShape(shape.shapePoints(), ShapeFormatCmp6.Precision()))
+		} else {
+			parts = append(parts, raw[i])
+		}
+		v.Add(key, strings.Join(parts, "|"))
+	}
+
+	return nil
 }
 
 type StaticMapRequest struct {
@@ -276,14 +519,30 @@ type StaticMapRequest struct {
 	// banner
 	Banner *StaticMapBanner `url:"banner,omitempty"`
 
-	// routes
-	Start *StaticMapLocation `url:"start,omitempty"`
-	End   *StaticMapLocation `url:"end,omitempty"`
-	// TODO: https://developer.mapquest.com/documentation/open/static-map-api/v5/map/#request_parameters-session
-	RotueArc   bool            `url:"routeArc,omitempty"`
-	RouteWidth int             `url:"routeWidth,omitempty"`
-	RouteColor *StaticMapColor `url:"routeColor,omitempty"`
+	// routes, either calculated from start to end, or given by the
+	// session ID of a route calculated before; the markers of start and
+	// end are styled through StaticMapLocation.Marker
+	Start      *StaticMapLocation `url:"start,omitempty"`
+	End        *StaticMapLocation `url:"end,omitempty"`
+	Session    string             `url:"session,omitempty"`
+	RouteArc   bool               `url:"routeArc,omitempty"`
+	RouteWidth int                `url:"routeWidth,omitempty"`
+	RouteColor *StaticMapColor    `url:"routeColor,omitempty"`
+
+	// Deprecated: use RouteArc.
+	RotueArc bool `url:"-"`
 
 	// shapemagics
-	// TODO: https://developer.mapquest.com/documentation/open/static-map-api/v5/map/#request_parameters-shape
+	Shapes StaticMapShapes `url:"shape,omitempty"`
+}
+
+// validate rejects route parameters the API would not accept together.
+func (req *StaticMapRequest) validate() error {
+	if req.Session != "" && (req.Start != nil || req.End != nil) {
+		return ErrStaticMapRouteConflict
+	}
+	if (req.Start == nil) != (req.End == nil) {
+		return ErrStaticMapRouteIncomplete
+	}
+	return nil
 }
@@ -0,0 +1,121 @@
+package mapquest_test
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/cking/mapquest"
+)
+
+func TestStaticMapCompressedShapes(t *testing.T) {
+	// a long track, so the shapes get compressed; its steps encode to "w|Av|A"
+	track := make([]mapquest.GeoPoint, 100)
+	for i := range track {
+		track[i] = mapquest.GeoPoint{Latitude: 38.5 + float64(i)*0.0015, Longitude: -120.2 - float64(i)*0.0015}
+	}
+	circle := mapquest.GeoPoint{Latitude: 40.7, Longitude: -120.95}
+
+	req := &mapquest.StaticMapRequest{Shapes: mapquest.StaticMapShapes{
+		&mapquest.StaticMapPolyline{Points: track, Color: mapquest.StaticMapColorHex(0x0000ff), Width: 3},
+		&mapquest.StaticMapCircle{Center: circle, Radius: 5},
+	}}
+	u, err := mapquest.NewClient("key").StaticMap().URL(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	shapes := u.Query()["shape"]
+	if len(shapes) != 2 {
+		t.Fatalf("got %d shape parameters, want 2", len(shapes))
+	}
+	for i, want := range []struct {
+		options string
+		points  []mapquest.GeoPoint
+	}{
+		{"border:0000ff|width:3|", track},
+		{"radius:5km|", []mapquest.GeoPoint{circle}},
+	} {
+		options, path, ok := strings.Cut(shapes[i], "cmp6|enc:")
+		if !ok || options != want.options {
+			t.Errorf("shape %d: got %q, want options %q followed by a compressed path", i, shapes[i], want.options)
+			continue
+		}
+		if i == 0 && !strings.Contains(path, "|") {
+			t.Errorf("shape %d: path %q does not contain a |", i, path)
+		}
+
+		points, err := mapquest.DecodeCompressedShape(path, 6)
+		if err != nil {
+			t.Errorf("shape %d: %v", i, err)
+			continue
+		}
+		if len(points) != len(want.points) {
+			t.Errorf("shape %d: decoded %d points, want %d", i, len(points), len(want.points))
+			continue
+		}
+		for j, p := range want.points {
+			if math.Abs(points[j].Latitude-p.Latitude) > 1e-6 || math.Abs(points[j].Longitude-p.Longitude) > 1e-6 {
+				t.Errorf("shape %d: point %d decoded as %v, want %v", i, j, points[j], p)
+			}
+		}
+	}
+}
+
+func TestStaticMapRoute(t *testing.T) {
+	api := mapquest.NewClient("key").StaticMap()
+
+	u, err := api.URL(&mapquest.StaticMapRequest{
+		Session:    "5f9b1a2c",
+		RouteArc:   true,
+		RouteWidth: 5,
+		RouteColor: mapquest.StaticMapColorHexAlpha(0x3366cc80),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := u.Query()
+	for param, want := range map[string]string{
+		"session":    "5f9b1a2c",
+		"routeArc":   "true",
+		"routeWidth": "5",
+		"routeColor": "3366cc80",
+	} {
+		if got := q.Get(param); got != want {
+			t.Errorf("%s: got %q, want %q", param, got, want)
+		}
+	}
+
+	// the misspelled field still works
+	u, err = api.URL(&mapquest.StaticMapRequest{Session: "5f9b1a2c", RotueArc: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := u.Query()["routeArc"]; len(got) != 1 || got[0] != "true" {
+		t.Errorf("deprecated RotueArc: got routeArc %q, want [true]", got)
+	}
+
+	u, err = api.URL(&mapquest.StaticMapRequest{
+		Start: &mapquest.StaticMapLocation{Location: "York, PA", Marker: "flag-start"},
+		End:   &mapquest.StaticMapLocation{Location: "Lancaster, PA", Marker: "flag-end"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := u.Query().Get("start"), "York, PA|flag-start"; got != want {
+		t.Errorf("start: got %q, want %q", got, want)
+	}
+
+	for _, tt := range []struct {
+		req  *mapquest.StaticMapRequest
+		want error
+	}{
+		{&mapquest.StaticMapRequest{Session: "5f9b1a2c", Start: &mapquest.StaticMapLocation{Location: "York, PA"}}, mapquest.ErrStaticMapRouteConflict},
+		{&mapquest.StaticMapRequest{End: &mapquest.StaticMapLocation{Location: "York, PA"}}, mapquest.ErrStaticMapRouteIncomplete},
+	} {
+		if _, err := api.URL(tt.req); !errors.Is(err, tt.want) {
+			t.Errorf("got %v, want %v", err, tt.want)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package mapquest
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/url"
 )
@@ -20,11 +21,93 @@ func (s *GeoPoint) EncodeValues(key string, v *url.Values) error {
 }
 
 type BoundingBox struct {
-	TopLeft     GeoPoint
-	BottomRight GeoPoint
+	TopLeft     GeoPoint `json:"ul"`
+	BottomRight GeoPoint `json:"lr"`
 }
 
 func (s *BoundingBox) EncodeValues(key string, v *url.Values) error {
 	v.Set(key, fmt.Sprintf("%v,%v", s.TopLeft.String(), s.BottomRight.String()))
 	return nil
 }
+
+// ResponseInfo is the info block MapQuest attaches to its responses.
+type ResponseInfo struct {
+	StatusCode int        `json:"statuscode,omitempty"` // https://developer.mapquest.com/documentation/geocoding-api/status-codes
+	Copyright  *Copyright `json:"copyright,omitempty"`
+	Messages   []string   `json:"messages,omitempty"`
+}
+
+type Copyright struct {
+	Text         string `json:"text,omitempty"`
+	ImageURL     string `json:"imageUrl,omitempty"`
+	ImageAltText string `json:"imageAltText,omitempty"`
+}
+
+// GeocodeAddress is a structured address, also known as 5-box input.
+type GeocodeAddress struct {
+	Street     string `json:"street,omitempty"`
+	City       string `json:"city,omitempty"`
+	County     string `json:"county,omitempty"`
+	State      string `json:"state,omitempty"`
+	PostalCode string `json:"postalCode,omitempty"`
+	Country    string `json:"country,omitempty"`
+}
+
+func (s *GeocodeAddress) EncodeValues(key string, v *url.Values) error {
+	// the 5-box fields are passed as separate parameters, key is not used
+	for param, value := range map[string]string{
+		"street":     s.Street,
+		"city":       s.City,
+		"county":     s.County,
+		"state":      s.State,
+		"postalCode": s.PostalCode,
+		"country":    s.Country,
+	} {
+		if value != "" {
+			v.Set(param, value)
+		}
+	}
+	return nil
+}
+
+// Location is an input location of the APIs accepting several kinds of
+// locations. Set exactly one of its fields.
+type Location struct {
+	// Text is a single line address, e.g. "1090 N Charlotte St, Lancaster, PA".
+	Text string
+	// LatLong is a coordinate.
+	LatLong *GeoPoint
+	// Address is a structured address.
+	Address *GeocodeAddress
+}
+
+// validate rejects a location setting more than one of its fields.
+func (s Location) validate() error {
+	n := 0
+	if s.Text != "" {
+		n++
+	}
+	if s.LatLong != nil {
+		n++
+	}
+	if s.Address != nil {
+		n++
+	}
+	if n > 1 {
+		return ErrLocationConflict
+	}
+	return nil
+}
+
+func (s Location) MarshalJSON() ([]byte, error) {
+	switch {
+	case s.Address != nil:
+		return json.Marshal(s.Address)
+	case s.LatLong != nil:
+		return json.Marshal(struct {
+			LatLong *GeoPoint `json:"latLng"`
+		}{s.LatLong})
+	default:
+		return json.Marshal(s.Text)
+	}
+}